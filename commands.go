@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"gitnotifier/config"
+	"gitnotifier/internal/issue"
+	"gitnotifier/internal/notifier"
+	"gitnotifier/internal/notifier/platform"
+	"gitnotifier/internal/repository"
+	"gitnotifier/internal/state"
+	"gitnotifier/internal/webhook"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+)
+
+// runList prints the open issues of each watched repository and owner as a
+// table, or as a JSON array of issues, without sending any notifications
+func runList(ctx context.Context, watched []watchedRepo, owners []watchedOwner, asJSON bool) error {
+	var all []issue.Issue
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	if !asJSON {
+		fmt.Fprintln(tw, "REPO\tNUMBER\tAGE\tTITLE\tURL")
+	}
+	type source struct {
+		label string
+		repo  repository.IssueRepository
+	}
+	var sources []source
+	for _, w := range watched {
+		sources = append(sources, source{w.key(), w.client})
+	}
+	for _, w := range owners {
+		sources = append(sources, source{w.owner, w.client})
+	}
+
+	for _, src := range sources {
+		issues, err := src.repo.FetchLatestIssues(ctx)
+		if err != nil {
+			return fmt.Errorf("%s: %v", src.label, err)
+		}
+		all = append(all, issues...)
+		if asJSON {
+			continue
+		}
+		for _, i := range issues {
+			name := i.RepoFullName()
+			if name == "" {
+				name = src.label
+			}
+			fmt.Fprintf(tw, "%s\t#%d\t%s\t%s\t%s\n", name, i.Number, notifier.FormatAge(time.Since(i.CreatedAt)), i.Title, i.HTMLURL)
+		}
+	}
+
+	if asJSON {
+		if all == nil {
+			all = []issue.Issue{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(all)
+	}
+	return tw.Flush()
+}
+
+// runWebhookReceiver notifies about issue events delivered by GitHub webhooks
+// until interrupted. Deliveries can come from any number of repositories, so
+// notifications are tagged with the issue's owner/repo.
+func runWebhookReceiver(tail bool) error {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	n, err := notifier.FromEnv(ctx, newHTTPClient(nil), tail, nil, func() platform.Icons { return nil })
+	if err != nil {
+		return fmt.Errorf("error initializing notifier: %v", err)
+	}
+	issueNotifier := notifier.NewIssueNotifier(n)
+	issueNotifier.TagWithRepo = true
+
+	// Redeliveries of an already handled delivery are ignored within WEBHOOK_DELIVERY_TTL
+	deliveryTTL := config.WebhookDeliveryTTL
+	if v := os.Getenv("WEBHOOK_DELIVERY_TTL"); v != "" {
+		if deliveryTTL, err = time.ParseDuration(v); err != nil || deliveryTTL < 0 {
+			return fmt.Errorf("invalid WEBHOOK_DELIVERY_TTL %q. Expected a duration of at least 0", v)
+		}
+	}
+
+	receiver, err := webhook.NewReceiver(os.Getenv("WEBHOOK_SECRET"), issueNotifier, deliveryTTL)
+	if err != nil {
+		return err
+	}
+	addr := os.Getenv("WEBHOOK_ADDR")
+	if addr == "" {
+		addr = config.DefaultWebhookAddr
+	}
+	return receiver.Run(ctx, addr)
+}
+
+// runResetState clears the persisted state of a single view, including the
+// issues it notified, or of everything with "all". Views are named like their
+// state keys: "owner/repo", "owner:name" for a user or org, "query:name" for a QUERIES entry.
+func runResetState(target string) error {
+	store, err := loadState()
+	if err != nil {
+		return err
+	}
+
+	if target == "all" {
+		if err := store.Reset(); err != nil {
+			return err
+		}
+		slog.Info("Cleared all persisted state")
+		return nil
+	}
+
+	if !validStateKey(target) {
+		return fmt.Errorf("invalid target %q. Expected 'owner/repo', 'owner:name', 'query:name' or 'all'", target)
+	}
+
+	found, err := store.ResetRepo(target)
+	if err != nil {
+		return err
+	}
+	if !found {
+		slog.Info("No persisted state found", "key", target)
+		return nil
+	}
+	slog.Info("Cleared persisted state", "key", target)
+	return nil
+}
+
+// validStateKey reports whether key has the shape of a key services store
+// state under: "owner/repo", "owner:name" or "query:name"
+func validStateKey(key string) bool {
+	if kind, name, ok := strings.Cut(key, ":"); ok {
+		return (kind == "owner" || kind == "query") && name != ""
+	}
+	owner, repo, ok := strings.Cut(key, "/")
+	return ok && owner != "" && repo != ""
+}
+
+// loadConfig reads the --config file, or fetches it when location is an
+// http(s) URL. A remote config is fetched with the CONFIG_AUTH_HEADER header
+// and its last good copy is kept in CONFIG_CACHE_FILE.
+func loadConfig(location string) (*config.Config, error) {
+	if !config.IsURL(location) {
+		return config.LoadFile(location)
+	}
+
+	cachePath := os.Getenv("CONFIG_CACHE_FILE")
+	if cachePath == "" {
+		var err error
+		if cachePath, err = config.DefaultCachePath(); err != nil {
+			slog.Warn("Not caching the remote config", "event", "config", "error", err)
+		}
+	}
+	return config.LoadURL(context.Background(), newHTTPClient(nil), location, os.Getenv("CONFIG_AUTH_HEADER"), cachePath)
+}
+
+// loadState opens the persisted state file from STATE_FILE or the default location.
+// A corrupt file is logged and replaced with fresh state rather than treated as fatal,
+// but an encrypted file that can't be decrypted is an error.
+func loadState() (*state.Store, error) {
+	path := os.Getenv("STATE_FILE")
+	if path == "" {
+		var err error
+		if path, err = state.DefaultPath(); err != nil {
+			return nil, err
+		}
+	}
+
+	var opts []state.Option
+	if key := os.Getenv("STATE_ENCRYPTION_KEY"); key != "" {
+		opts = append(opts, state.WithPassphrase(key))
+	}
+
+	store := state.NewStore(path, opts...)
+	if err := store.Load(); err != nil {
+		if state.IsKeyError(err) {
+			return nil, err
+		}
+		slog.Warn("Starting with fresh state", "error", err)
+	}
+	return store, nil
+}
@@ -4,11 +4,43 @@ import "time"
 
 // Constants for service configuration
 const (
-	MaxNotificationLength = 100
-	MinPollInterval       = 1 * time.Minute
-	DefaultPollInterval   = 5 * time.Minute
-	MaxRetries            = 3
-	RetryDelay            = 5 * time.Second
-	HTTPTimeout           = 10 * time.Second
-	NotifyDelay           = 500 * time.Millisecond // Prevent notification flooding
+	MaxNotificationLength    = 100
+	MinPollInterval          = 1 * time.Minute
+	DefaultPollInterval      = 5 * time.Minute
+	MaxAdaptivePollInterval  = 30 * time.Minute
+	MaxRetries               = 3
+	RetryBaseDelay           = 1 * time.Second  // First GitHub API retry delay, doubled on each attempt
+	RetryMaxDelay            = 30 * time.Second // Cap on the GitHub API retry delay
+	MaxRetryAfter            = 5 * time.Minute  // Cap on a Retry-After delay requested by GitHub
+	HTTPTimeout              = 10 * time.Second // Overall limit on an HTTP request
+	DialTimeout              = 5 * time.Second
+	TLSHandshakeTimeout      = 5 * time.Second
+	ResponseHeaderTimeout    = 10 * time.Second
+	ExecTimeout              = 10 * time.Second
+	NotifyDelay              = 500 * time.Millisecond // Prevent notification flooding
+	DefaultNotifyRetries     = 2                      // Extra attempts for a failed new-issue notification
+	NotifyRetryDelay         = 2 * time.Second
+	UpdateCheckInterval      = 24 * time.Hour
+	MilestoneScanInterval    = 24 * time.Hour
+	DefaultStarMilestoneStep = 100
+	MaxBodyScanLength        = 8 * 1024       // Bytes of an issue body checked by body filters
+	DefaultDedupTTL          = 1 * time.Hour  // Window for suppressing repeat notifications of an issue
+	DefaultNotifiedTTL       = 24 * time.Hour // How long notified issue IDs are remembered across restarts
+	DefaultNotifiedSize      = 500            // Notified issue IDs kept in the state file
+	MetricsFlushInterval     = 10 * time.Second
+	DefaultPerPage           = 10
+	DefaultMaxCatchUpPages   = 10  // Pages followed to catch up on a burst of new issues
+	MaxPerPage               = 100 // GitHub API page size limit
+	DefaultBatchThreshold    = 3   // New issues per poll above which one summary is sent
+	DefaultLinkedPRLookups   = 5   // Linked pull request lookups per poll
+	DefaultAPIBase           = "https://api.github.com"
+	MaxConsecutivePanics     = 5 // Polls in a row that may panic before the service gives up
+	PauseFileCheckInterval   = 5 * time.Second
+	RecentIssuesSize         = 50               // Notified issues kept for the REST API
+	MaxKnownStates           = 5000             // Issue states remembered for WATCH_TRANSITIONS
+	ShutdownTimeout          = 10 * time.Second // Wait for in-flight notifications on shutdown
+	ServerHeaderTimeout      = 10 * time.Second // Limit on reading request headers for the HTTP endpoints
+	ServerShutdownTimeout    = 5 * time.Second  // Wait for in-flight HTTP requests on shutdown
+	DefaultWebhookAddr       = ":8080"
+	WebhookDeliveryTTL       = 1 * time.Hour // Window for ignoring redelivered webhooks
 )
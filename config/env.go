@@ -0,0 +1,15 @@
+package config
+
+import "strings"
+
+// SplitList splits a comma-separated list, trimming whitespace and dropping
+// empty entries, as used by list settings such as LABELS
+func SplitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,110 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the structure of a config file, an alternative to setting
+// environment variables. Every field maps to the environment variable named
+// in its comment, and Settings holds any other variable by name, e.g.
+// SLACK_WEBHOOK_URL: https://hooks.slack.com/...
+type Config struct {
+	RepoURLs     []string          `json:"repo_urls" yaml:"repo_urls"`         // GITHUB_REPO_URL
+	Token        string            `json:"token" yaml:"token"`                 // GITHUB_TOKEN
+	PollInterval string            `json:"poll_interval" yaml:"poll_interval"` // POLL_INTERVAL
+	Labels       []string          `json:"labels" yaml:"labels"`               // LABELS
+	Notifier     []string          `json:"notifier" yaml:"notifier"`           // NOTIFIER
+	Settings     map[string]string `json:"settings" yaml:"settings"`
+}
+
+// LoadFile reads a YAML (.yaml or .yml) or JSON (.json) config file. Unknown
+// fields are rejected so typos don't go unnoticed.
+func LoadFile(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening config file: %v", err)
+	}
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		return parse(data, "yaml", path)
+	case ".json":
+		return parse(data, "json", path)
+	default:
+		return nil, fmt.Errorf("unsupported config file %s. Expected a .yaml, .yml or .json file", path)
+	}
+}
+
+// parse decodes and validates a config in format, "yaml" or "json", read from source
+func parse(data []byte, format, source string) (*Config, error) {
+	var c Config
+	var err error
+	if format == "json" {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(&c)
+	} else {
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		err = dec.Decode(&c)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error parsing config %s: %v", source, err)
+	}
+	if c.PollInterval != "" {
+		if _, err := ParsePollInterval(c.PollInterval); err != nil {
+			return nil, fmt.Errorf("invalid poll_interval in %s: %v", source, err)
+		}
+	}
+	return &c, nil
+}
+
+// ParsePollInterval parses a duration such as "5m", or a bare number of seconds
+func ParsePollInterval(s string) (time.Duration, error) {
+	if seconds, err := strconv.Atoi(s); err == nil {
+		return time.Duration(seconds) * time.Second, nil
+	}
+	return time.ParseDuration(s)
+}
+
+// Env returns the settings of c as environment variables
+func (c *Config) Env() map[string]string {
+	env := make(map[string]string, len(c.Settings)+5)
+	for k, v := range c.Settings {
+		env[k] = v
+	}
+	set := func(key, value string) {
+		if value != "" {
+			env[key] = value
+		}
+	}
+	set("GITHUB_REPO_URL", strings.Join(c.RepoURLs, ","))
+	set("GITHUB_TOKEN", c.Token)
+	set("POLL_INTERVAL", c.PollInterval)
+	set("LABELS", strings.Join(c.Labels, ","))
+	set("NOTIFIER", strings.Join(c.Notifier, ","))
+	return env
+}
+
+// Apply sets the environment variables from c that are not already set, so
+// the environment overrides the file
+func (c *Config) Apply() error {
+	for k, v := range c.Env() {
+		if _, ok := os.LookupEnv(k); ok {
+			continue
+		}
+		if err := os.Setenv(k, v); err != nil {
+			return fmt.Errorf("error applying config setting %s: %v", k, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeConfig writes content to a file called name in a temporary directory
+func writeConfig(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadFile(t *testing.T) {
+	tests := []struct {
+		name, file, content string
+	}{
+		{"yaml", "config.yaml", `
+repo_urls:
+  - https://github.com/owner/repo
+  - https://github.com/owner/other
+token: secret
+poll_interval: 90
+labels: [bug]
+settings:
+  SLACK_WEBHOOK_URL: https://hooks.slack.com/services/x
+`},
+		{"yml", "config.yml", `
+repo_urls: [https://github.com/owner/repo, https://github.com/owner/other]
+token: secret
+poll_interval: "90"
+labels: [bug]
+settings: {SLACK_WEBHOOK_URL: "https://hooks.slack.com/services/x"}
+`},
+		{"json", "config.json", `{
+  "repo_urls": ["https://github.com/owner/repo", "https://github.com/owner/other"],
+  "token": "secret",
+  "poll_interval": "90",
+  "labels": ["bug"],
+  "settings": {"SLACK_WEBHOOK_URL": "https://hooks.slack.com/services/x"}
+}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := LoadFile(writeConfig(t, tt.file, tt.content))
+			if err != nil {
+				t.Fatalf("LoadFile() error = %v", err)
+			}
+			env := c.Env()
+			want := map[string]string{
+				"GITHUB_REPO_URL":   "https://github.com/owner/repo,https://github.com/owner/other",
+				"GITHUB_TOKEN":      "secret",
+				"POLL_INTERVAL":     "90",
+				"LABELS":            "bug",
+				"SLACK_WEBHOOK_URL": "https://hooks.slack.com/services/x",
+			}
+			for k, v := range want {
+				if env[k] != v {
+					t.Errorf("Env()[%s] = %q, want %q", k, env[k], v)
+				}
+			}
+			if _, ok := env["NOTIFIER"]; ok {
+				t.Errorf("Env() sets NOTIFIER = %q for an empty list", env["NOTIFIER"])
+			}
+		})
+	}
+}
+
+func TestLoadFileInvalid(t *testing.T) {
+	tests := []struct {
+		name, file, content, want string
+	}{
+		{"unknown yaml field", "config.yaml", "repo_url: https://github.com/owner/repo\n", "repo_url"},
+		{"unknown json field", "config.json", `{"repo_url": "https://github.com/owner/repo"}`, "repo_url"},
+		{"invalid poll interval", "config.yaml", "poll_interval: soon\n", "poll_interval"},
+		{"malformed yaml", "config.yaml", "labels: [bug\n", "error parsing"},
+		{"unsupported extension", "config.toml", "token = \"secret\"\n", "unsupported"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadFile(writeConfig(t, tt.file, tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("LoadFile() error = %v, want one mentioning %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadFileMissing(t *testing.T) {
+	if _, err := LoadFile(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("LoadFile() of a missing file succeeded")
+	}
+}
+
+func TestApplyKeepsEnvironment(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "from-env")
+	for _, k := range []string{"POLL_INTERVAL", "GN_TEST_SETTING"} {
+		t.Setenv(k, "")
+		os.Unsetenv(k)
+	}
+
+	c := &Config{
+		Token:        "from-file",
+		PollInterval: "10m",
+		Settings:     map[string]string{"GN_TEST_SETTING": "from-file"},
+	}
+	if err := c.Apply(); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+
+	tests := []struct {
+		key, want string
+	}{
+		{"GITHUB_TOKEN", "from-env"},
+		{"POLL_INTERVAL", "10m"},
+		{"GN_TEST_SETTING", "from-file"},
+	}
+	for _, tt := range tests {
+		if got := os.Getenv(tt.key); got != tt.want {
+			t.Errorf("%s = %q after Apply, want %q", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestParsePollInterval(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"5m", "5m0s", false},
+		{"90", "1m30s", false},
+		{"1h30m", "1h30m0s", false},
+		{"soon", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParsePollInterval(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParsePollInterval(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if err == nil && got.String() != tt.want {
+			t.Errorf("ParsePollInterval(%q) = %v, want %s", tt.in, got, tt.want)
+		}
+	}
+}
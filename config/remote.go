@@ -0,0 +1,129 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// maxRemoteConfigSize bounds the body read from a config URL
+const maxRemoteConfigSize = 1 << 20
+
+// cachedConfig is the last good remote config, saved with the URL it came from
+type cachedConfig struct {
+	URL    string  `json:"url"`
+	Config *Config `json:"config"`
+}
+
+// IsURL reports whether a --config value is an http(s) URL rather than a file path
+func IsURL(location string) bool {
+	return strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://")
+}
+
+// DefaultCachePath returns where the last good remote config is kept, under
+// the user's cache directory
+func DefaultCachePath() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("error locating cache directory: %v", err)
+	}
+	return filepath.Join(dir, "gitnotifier", "config.json"), nil
+}
+
+// LoadURL fetches a YAML or JSON config from rawURL through client and
+// validates it like LoadFile. authHeader, e.g. "Authorization: Bearer xyz", is
+// sent when set. A good config is saved to cachePath, and when fetching fails
+// the copy saved from the same URL is used instead so a transient outage
+// doesn't stop the service. An empty cachePath turns the cache off.
+func LoadURL(ctx context.Context, client *http.Client, rawURL, authHeader, cachePath string) (*Config, error) {
+	header := make(http.Header)
+	if authHeader != "" {
+		name, value, ok := strings.Cut(authHeader, ":")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("invalid config auth header. Expected \"Name: value\"")
+		}
+		header.Set(strings.TrimSpace(name), strings.TrimSpace(value))
+	}
+
+	c, err := fetchConfig(ctx, client, rawURL, header)
+	if err == nil {
+		if cachePath != "" {
+			if err := saveCachedConfig(cachePath, rawURL, c); err != nil {
+				slog.Warn("Error caching the remote config", "event", "config", "error", err)
+			}
+		}
+		return c, nil
+	}
+	if cachePath == "" {
+		return nil, err
+	}
+
+	cached, cacheErr := loadCachedConfig(cachePath, rawURL)
+	if cacheErr != nil {
+		return nil, fmt.Errorf("%v, and there is no cached copy: %v", err, cacheErr)
+	}
+	slog.Warn("Error fetching the remote config, using the last good copy", "event", "config", "cache", cachePath, "error", err)
+	return cached, nil
+}
+
+func fetchConfig(ctx context.Context, client *http.Client, rawURL string, header http.Header) (*Config, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config URL: %v", err)
+	}
+	req.Header = header
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching config: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error fetching config: status code %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxRemoteConfigSize))
+	if err != nil {
+		return nil, fmt.Errorf("error fetching config: %v", err)
+	}
+
+	// JSON when the URL or the response says so, YAML otherwise
+	format := "yaml"
+	if strings.EqualFold(path.Ext(req.URL.Path), ".json") || strings.Contains(resp.Header.Get("Content-Type"), "json") {
+		format = "json"
+	}
+	return parse(data, format, req.URL.Redacted())
+}
+
+func saveCachedConfig(cachePath, rawURL string, c *Config) error {
+	data, err := json.Marshal(cachedConfig{URL: rawURL, Config: c})
+	if err != nil {
+		return err
+	}
+	// The config may hold tokens, so only the user can read the copy
+	if err := os.MkdirAll(filepath.Dir(cachePath), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(cachePath, data, 0o600)
+}
+
+func loadCachedConfig(cachePath, rawURL string) (*Config, error) {
+	data, err := os.ReadFile(cachePath)
+	if err != nil {
+		return nil, err
+	}
+	var cached cachedConfig
+	if err := json.Unmarshal(data, &cached); err != nil {
+		return nil, fmt.Errorf("error parsing %s: %v", cachePath, err)
+	}
+	if cached.URL != rawURL || cached.Config == nil {
+		return nil, fmt.Errorf("%s holds the config of another URL", cachePath)
+	}
+	return cached.Config, nil
+}
@@ -0,0 +1,124 @@
+package config
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const remoteYAML = "repo_urls: [https://github.com/owner/repo]\npoll_interval: 10m\n"
+
+// configServer serves body, or a 503 while down is set, to requests with the expected token
+type configServer struct {
+	body        string
+	contentType string
+	down        atomic.Bool
+}
+
+func (c *configServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Header.Get("Authorization") != "Bearer s3cret" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if c.down.Load() {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	if c.contentType != "" {
+		w.Header().Set("Content-Type", c.contentType)
+	}
+	io.WriteString(w, c.body)
+}
+
+func newConfigServer(t *testing.T, body, contentType string) (*configServer, string) {
+	t.Helper()
+	c := &configServer{body: body, contentType: contentType}
+	srv := httptest.NewServer(c)
+	t.Cleanup(srv.Close)
+	return c, srv.URL + "/gitnotifier"
+}
+
+var testClient = &http.Client{Timeout: 5 * time.Second}
+
+func TestLoadURL(t *testing.T) {
+	tests := []struct {
+		name, body, contentType string
+	}{
+		{"yaml", remoteYAML, "application/yaml"},
+		{"json", `{"repo_urls": ["https://github.com/owner/repo"], "poll_interval": "10m"}`, "application/json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, url := newConfigServer(t, tt.body, tt.contentType)
+			c, err := LoadURL(context.Background(), testClient, url, "Authorization: Bearer s3cret", "")
+			if err != nil {
+				t.Fatalf("LoadURL() error = %v", err)
+			}
+			if c.PollInterval != "10m" || len(c.RepoURLs) != 1 {
+				t.Errorf("LoadURL() = %+v", c)
+			}
+		})
+	}
+}
+
+func TestLoadURLInvalid(t *testing.T) {
+	tests := []struct {
+		name, body, header, want string
+	}{
+		{"unknown field", "repo_url: https://github.com/owner/repo\n", "Authorization: Bearer s3cret", "repo_url"},
+		{"invalid poll interval", "poll_interval: soon\n", "Authorization: Bearer s3cret", "poll_interval"},
+		{"rejected auth header", remoteYAML, "Authorization: Bearer wrong", "401"},
+		{"malformed auth header", remoteYAML, "Bearer s3cret", "auth header"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, url := newConfigServer(t, tt.body, "")
+			_, err := LoadURL(context.Background(), testClient, url, tt.header, "")
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("LoadURL() error = %v, want one mentioning %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadURLFallsBackToCache(t *testing.T) {
+	cache := filepath.Join(t.TempDir(), "gitnotifier", "config.json")
+	srv, url := newConfigServer(t, remoteYAML, "")
+
+	if _, err := LoadURL(context.Background(), testClient, url, "Authorization: Bearer s3cret", cache); err != nil {
+		t.Fatalf("LoadURL() error = %v", err)
+	}
+
+	srv.down.Store(true)
+	c, err := LoadURL(context.Background(), testClient, url, "Authorization: Bearer s3cret", cache)
+	if err != nil {
+		t.Fatalf("LoadURL() while the server is down error = %v, want the cached copy", err)
+	}
+	if c.PollInterval != "10m" {
+		t.Errorf("cached PollInterval = %q, want 10m", c.PollInterval)
+	}
+
+	_, otherURL := newConfigServer(t, remoteYAML, "")
+	if _, err := LoadURL(context.Background(), testClient, otherURL+"-missing", "", cache); err == nil {
+		t.Error("LoadURL() used the cached config of another URL")
+	}
+}
+
+func TestIsURL(t *testing.T) {
+	for in, want := range map[string]bool{
+		"https://example.com/config.yaml": true,
+		"http://example.com/config.yaml":  true,
+		"config.yaml":                     false,
+		"/etc/gitnotifier/config.json":    false,
+	} {
+		if got := IsURL(in); got != want {
+			t.Errorf("IsURL(%q) = %v, want %v", in, got, want)
+		}
+	}
+}
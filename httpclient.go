@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"gitnotifier/config"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// newHTTPClient creates a client for outgoing requests. It goes through proxy
+// when set, otherwise through the proxy from HTTP_PROXY, HTTPS_PROXY and NO_PROXY.
+// HTTP_TIMEOUT bounds a whole request, while HTTP_DIAL_TIMEOUT,
+// HTTP_TLS_TIMEOUT and HTTP_RESPONSE_HEADER_TIMEOUT catch dead connections early.
+func newHTTPClient(proxy *url.URL) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyFromEnvironment
+	if proxy != nil {
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+	dialer := &net.Dialer{
+		Timeout:   envDuration("HTTP_DIAL_TIMEOUT", config.DialTimeout),
+		KeepAlive: 30 * time.Second,
+	}
+	transport.DialContext = dialer.DialContext
+	transport.TLSHandshakeTimeout = envDuration("HTTP_TLS_TIMEOUT", config.TLSHandshakeTimeout)
+	transport.ResponseHeaderTimeout = envDuration("HTTP_RESPONSE_HEADER_TIMEOUT", config.ResponseHeaderTimeout)
+
+	return &http.Client{
+		Timeout:   envDuration("HTTP_TIMEOUT", config.HTTPTimeout),
+		Transport: transport,
+	}
+}
+
+// envDuration reads a positive duration such as 30s from the environment
+// variable name, returning def when it is unset
+func envDuration(name string, def time.Duration) time.Duration {
+	v := os.Getenv(name)
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		log.Fatalf("Invalid %s %q. Expected a positive duration like 30s", name, v)
+	}
+	return d
+}
+
+// parseProxyURL parses an http, https or socks5 proxy URL. An empty value means no proxy.
+func parseProxyURL(v string) (*url.URL, error) {
+	if v == "" {
+		return nil, nil
+	}
+	u, err := url.Parse(v)
+	if err != nil {
+		return nil, err
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme %q. Expected http, https or socks5", u.Scheme)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("proxy URL %q has no host", v)
+	}
+	return u, nil
+}
@@ -0,0 +1,147 @@
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"gitnotifier/internal/httpserver"
+	"gitnotifier/internal/service"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// Watched is a service exposed by the API under a label such as "owner/repo"
+type Watched struct {
+	Label   string
+	Service *service.Service
+}
+
+// Pauser suspends and resumes notification delivery
+type Pauser interface {
+	Pause()
+	Resume()
+	Paused() bool
+}
+
+// RepoStatus is an element of the GET /repos response
+type RepoStatus struct {
+	Name        string     `json:"name"`
+	LastPoll    *time.Time `json:"last_poll,omitempty"`
+	LastError   string     `json:"last_error,omitempty"`
+	LastCheckID int        `json:"last_check_id"`
+}
+
+// RecentIssue is an element of the GET /issues/recent response
+type RecentIssue struct {
+	Number     int       `json:"number"`
+	Title      string    `json:"title"`
+	URL        string    `json:"url"`
+	Source     string    `json:"source,omitempty"`
+	NotifiedAt time.Time `json:"notified_at"`
+}
+
+// PauseState is the response of POST /pause and POST /resume
+type PauseState struct {
+	Paused bool `json:"paused"`
+}
+
+// ErrorResponse is returned with every non-2xx status
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// Server is a small REST API for querying and controlling a running notifier.
+// Every request must carry "Authorization: Bearer <token>".
+type Server struct {
+	token   string
+	watched []Watched
+	recent  *service.RecentIssues
+	pauser  Pauser
+}
+
+// NewServer creates an API server. token must not be empty.
+func NewServer(token string, watched []Watched, recent *service.RecentIssues, pauser Pauser) (*Server, error) {
+	if token == "" {
+		return nil, fmt.Errorf("an API auth token is required")
+	}
+	return &Server{
+		token:   token,
+		watched: watched,
+		recent:  recent,
+		pauser:  pauser,
+	}, nil
+}
+
+// Handler returns the API routes wrapped in bearer token authentication
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos", s.handleRepos)
+	mux.HandleFunc("GET /issues/recent", s.handleRecentIssues)
+	mux.HandleFunc("POST /pause", s.handlePause)
+	mux.HandleFunc("POST /resume", s.handleResume)
+	return s.authenticate(mux)
+}
+
+// Run serves the API on addr until ctx is cancelled
+func (s *Server) Run(ctx context.Context, addr string) error {
+	return httpserver.Run(ctx, addr, s.Handler(), "REST API", "api")
+}
+
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	expected := []byte("Bearer " + s.token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), expected) != 1 {
+			writeJSON(w, http.StatusUnauthorized, ErrorResponse{Error: "missing or invalid bearer token"})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) handleRepos(w http.ResponseWriter, r *http.Request) {
+	repos := make([]RepoStatus, 0, len(s.watched))
+	for _, watched := range s.watched {
+		st := watched.Service.Status()
+		repo := RepoStatus{Name: watched.Label, LastError: st.LastError, LastCheckID: st.LastCheckID}
+		if !st.LastPoll.IsZero() {
+			repo.LastPoll = &st.LastPoll
+		}
+		repos = append(repos, repo)
+	}
+	writeJSON(w, http.StatusOK, repos)
+}
+
+func (s *Server) handleRecentIssues(w http.ResponseWriter, r *http.Request) {
+	notified := s.recent.List()
+	issues := make([]RecentIssue, 0, len(notified))
+	for _, n := range notified {
+		issues = append(issues, RecentIssue{
+			Number:     n.Issue.Number,
+			Title:      n.Issue.Title,
+			URL:        n.Issue.HTMLURL,
+			Source:     n.Source,
+			NotifiedAt: n.NotifiedAt,
+		})
+	}
+	writeJSON(w, http.StatusOK, issues)
+}
+
+func (s *Server) handlePause(w http.ResponseWriter, r *http.Request) {
+	s.pauser.Pause()
+	writeJSON(w, http.StatusOK, PauseState{Paused: s.pauser.Paused()})
+}
+
+func (s *Server) handleResume(w http.ResponseWriter, r *http.Request) {
+	s.pauser.Resume()
+	writeJSON(w, http.StatusOK, PauseState{Paused: s.pauser.Paused()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		slog.Error("Error writing API response", "event", "api", "error", err)
+	}
+}
@@ -0,0 +1,18 @@
+package ctxutil
+
+import (
+	"context"
+	"time"
+)
+
+// Linger returns a context that is cancelled d after ctx is, or when the
+// returned cancel function is called. It lets work in progress, such as a
+// notification being sent, finish after a shutdown instead of being abandoned.
+func Linger(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	lingerCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	stop := context.AfterFunc(ctx, func() { time.AfterFunc(d, cancel) })
+	return lingerCtx, func() {
+		stop()
+		cancel()
+	}
+}
@@ -0,0 +1,31 @@
+package ctxutil
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLingerOutlivesCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	lingerCtx, stop := Linger(ctx, 50*time.Millisecond)
+	defer stop()
+
+	cancel()
+	if err := Sleep(lingerCtx, 10*time.Millisecond); err != nil {
+		t.Fatalf("Sleep right after the cancel = %v, want nil", err)
+	}
+	select {
+	case <-lingerCtx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("lingering context was not cancelled after the grace period")
+	}
+}
+
+func TestLingerStop(t *testing.T) {
+	lingerCtx, stop := Linger(context.Background(), time.Minute)
+	stop()
+	if lingerCtx.Err() == nil {
+		t.Error("lingering context is still alive after stop")
+	}
+}
@@ -0,0 +1,24 @@
+package ctxutil
+
+import (
+	"context"
+	"time"
+)
+
+// Sleep pauses for the given duration or until the context is cancelled,
+// whichever comes first. It returns the context error if cancelled.
+func Sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
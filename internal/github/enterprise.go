@@ -0,0 +1,33 @@
+package github
+
+import (
+	"fmt"
+	"gitnotifier/config"
+	"net/url"
+	"strings"
+)
+
+// ResolveAPIBase derives the REST API root and the web URL from base, which
+// is either an Enterprise Server host ("https://github.example.com") or its
+// API root ("https://github.example.com/api/v3"). An empty base means github.com.
+func ResolveAPIBase(base string) (apiBase, webBase string, err error) {
+	base = strings.TrimSuffix(strings.TrimSpace(base), "/")
+	if base == "" {
+		return config.DefaultAPIBase, "https://github.com", nil
+	}
+
+	u, err := url.Parse(base)
+	if err != nil || u.Host == "" || (u.Scheme != "https" && u.Scheme != "http") {
+		return "", "", fmt.Errorf("invalid GitHub API base %q. Expected e.g. 'https://github.example.com'", base)
+	}
+	if u.Host == "api.github.com" || u.Host == "github.com" {
+		return config.DefaultAPIBase, "https://github.com", nil
+	}
+
+	// Enterprise Server serves the REST API under /api/v3 on the web host
+	webBase = u.Scheme + "://" + u.Host
+	if u.Path == "" {
+		return webBase + "/api/v3", webBase, nil
+	}
+	return base, webBase, nil
+}
@@ -6,22 +6,40 @@ import (
 )
 
 // ParseGitHubURL parses a GitHub repository URL into owner and repo parts
-// Only accepts full GitHub URLs in the format: https://github.com/owner/repo
+// Accepts https://github.com/owner/repo as well as the SSH and git:// clone
+// forms git@github.com:owner/repo.git, ssh://git@github.com/owner/repo and
+// git://github.com/owner/repo
 func ParseGitHubURL(url string) (owner, repo string, err error) {
+	return ParseRepoURL(url, "https://github.com")
+}
+
+// ParseRepoURL parses a repository URL under webBase, such as a GitHub
+// Enterprise Server at "https://github.example.com", into owner and repo parts.
+// A user or organization URL like "https://github.com/someorg" has an empty repo.
+func ParseRepoURL(url, webBase string) (owner, repo string, err error) {
 	url = strings.TrimSpace(url)
 	url = strings.TrimSuffix(url, "/")
 	url = strings.TrimSuffix(url, "/issues")
 
-	if !strings.HasPrefix(url, "https://github.com/") {
-		return "", "", fmt.Errorf("invalid GitHub URL format. URL must start with 'https://github.com/'")
+	prefix := strings.TrimSuffix(webBase, "/") + "/"
+	url = strings.TrimSuffix(cloneURLToWeb(url, prefix), ".git")
+	if !strings.HasPrefix(url, prefix) {
+		return "", "", fmt.Errorf("invalid GitHub URL format. URL must start with '%s'", prefix)
 	}
 
 	// Remove the prefix to get owner/repo part
-	repoPath := strings.TrimPrefix(url, "https://github.com/")
+	repoPath := strings.TrimPrefix(url, prefix)
 	parts := strings.Split(repoPath, "/")
 
-	if len(parts) != 2 {
-		return "", "", fmt.Errorf("invalid GitHub URL format. Expected 'https://github.com/owner/repo'")
+	if len(parts) > 2 {
+		return "", "", fmt.Errorf("invalid GitHub URL format. Expected '%sowner/repo' or '%sowner'", prefix, prefix)
+	}
+
+	if len(parts) == 1 {
+		if parts[0] == "" {
+			return "", "", fmt.Errorf("owner cannot be empty")
+		}
+		return parts[0], "", nil
 	}
 
 	if parts[0] == "" || parts[1] == "" {
@@ -30,3 +48,19 @@ func ParseGitHubURL(url string) (owner, repo string, err error) {
 
 	return parts[0], parts[1], nil
 }
+
+// cloneURLToWeb rewrites the SSH and git:// clone URLs of the host in prefix
+// to the web form, leaving any other URL unchanged
+func cloneURLToWeb(url, prefix string) string {
+	_, host, ok := strings.Cut(strings.TrimSuffix(prefix, "/"), "://")
+	if !ok {
+		return url
+	}
+
+	for _, clonePrefix := range []string{"git@" + host + ":", "ssh://git@" + host + "/", "git://" + host + "/"} {
+		if rest, ok := strings.CutPrefix(url, clonePrefix); ok {
+			return prefix + rest
+		}
+	}
+	return url
+}
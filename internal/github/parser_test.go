@@ -0,0 +1,70 @@
+package github
+
+import "testing"
+
+func TestParseGitHubURL(t *testing.T) {
+	tests := []struct {
+		url         string
+		owner, repo string
+	}{
+		{"https://github.com/foo/bar", "foo", "bar"},
+		{"https://github.com/foo/bar/", "foo", "bar"},
+		{"https://github.com/foo/bar/issues", "foo", "bar"},
+		{"https://github.com/foo/bar.git", "foo", "bar"},
+		{"  https://github.com/foo/bar  ", "foo", "bar"},
+		{"https://github.com/someorg", "someorg", ""},
+		{"git@github.com:foo/bar.git", "foo", "bar"},
+		{"git@github.com:foo/bar", "foo", "bar"},
+		{"ssh://git@github.com/foo/bar", "foo", "bar"},
+		{"ssh://git@github.com/foo/bar.git", "foo", "bar"},
+		{"git://github.com/foo/bar.git", "foo", "bar"},
+	}
+	for _, tt := range tests {
+		owner, repo, err := ParseGitHubURL(tt.url)
+		if err != nil || owner != tt.owner || repo != tt.repo {
+			t.Errorf("ParseGitHubURL(%q) = %q, %q, %v; want %q, %q", tt.url, owner, repo, err, tt.owner, tt.repo)
+		}
+	}
+}
+
+func TestParseGitHubURLInvalid(t *testing.T) {
+	for _, url := range []string{
+		"",
+		"github.com/foo/bar",
+		"http://github.com/foo/bar",
+		"https://gitlab.com/foo/bar",
+		"git@gitlab.com:foo/bar.git",
+		"https://github.com/",
+		"https://github.com/foo/bar/baz",
+		"https://github.com//bar",
+		"git@github.com:",
+		"git@github.com:foo/bar/baz.git",
+		"ssh://github.com/foo/bar",
+	} {
+		if owner, repo, err := ParseGitHubURL(url); err == nil {
+			t.Errorf("ParseGitHubURL(%q) = %q, %q, want an error", url, owner, repo)
+		}
+	}
+}
+
+func TestParseRepoURLEnterprise(t *testing.T) {
+	const base = "https://github.example.com/"
+	tests := []struct {
+		url         string
+		owner, repo string
+		wantErr     bool
+	}{
+		{"https://github.example.com/foo/bar", "foo", "bar", false},
+		{"git@github.example.com:foo/bar.git", "foo", "bar", false},
+		{"ssh://git@github.example.com/foo/bar", "foo", "bar", false},
+		{"https://github.example.com/someorg", "someorg", "", false},
+		{"https://github.com/foo/bar", "", "", true},
+		{"git@github.com:foo/bar.git", "", "", true},
+	}
+	for _, tt := range tests {
+		owner, repo, err := ParseRepoURL(tt.url, base)
+		if (err != nil) != tt.wantErr || owner != tt.owner || repo != tt.repo {
+			t.Errorf("ParseRepoURL(%q) = %q, %q, %v; want %q, %q, error %v", tt.url, owner, repo, err, tt.owner, tt.repo, tt.wantErr)
+		}
+	}
+}
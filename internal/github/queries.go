@@ -0,0 +1,56 @@
+package github
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NamedQuery is a user-defined issue search query
+type NamedQuery struct {
+	Name  string
+	Query string
+}
+
+// ParseQueries parses semicolon-separated name=query pairs such as
+// "triage=label:triage no:assignee;security=label:security"
+func ParseQueries(spec string) ([]NamedQuery, error) {
+	var queries []NamedQuery
+	seen := make(map[string]bool)
+
+	for _, part := range strings.Split(spec, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		name, query, ok := strings.Cut(part, "=")
+		name = strings.TrimSpace(name)
+		query = strings.TrimSpace(query)
+		if !ok || name == "" || query == "" {
+			return nil, fmt.Errorf("invalid query %q. Expected format 'name=search query'", part)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("duplicate query name %q", name)
+		}
+		seen[name] = true
+
+		queries = append(queries, NamedQuery{Name: name, Query: query})
+	}
+	return queries, nil
+}
+
+// ScopeQuery restricts query to owner/repo, or to every repository of owner
+// when repo is empty, unless it already targets a repo, org or user
+func ScopeQuery(query, owner, repo string) string {
+	for _, field := range strings.Fields(query) {
+		for _, qualifier := range []string{"repo:", "org:", "user:"} {
+			if strings.HasPrefix(field, qualifier) {
+				return query
+			}
+		}
+	}
+	if repo == "" {
+		return fmt.Sprintf("%s user:%s", query, owner)
+	}
+	return fmt.Sprintf("%s repo:%s/%s", query, owner, repo)
+}
@@ -0,0 +1,72 @@
+package github
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Tokens maps an owner ("my-org") or repository ("me/project") to the API token
+// used for it
+type Tokens map[string]string
+
+// ParseTokens parses semicolon-separated target=token pairs such as
+// "my-org=ghp_work;me/project=ghp_personal"
+func ParseTokens(spec string) (Tokens, error) {
+	tokens := make(Tokens)
+
+	for _, part := range strings.Split(spec, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		target, token, ok := strings.Cut(part, "=")
+		target = strings.ToLower(strings.TrimSpace(target))
+		token = strings.TrimSpace(token)
+		if !ok || target == "" || token == "" {
+			return nil, fmt.Errorf("invalid token entry for %q. Expected format 'owner=token' or 'owner/repo=token'", target)
+		}
+		if strings.Count(target, "/") > 1 {
+			return nil, fmt.Errorf("invalid token target %q. Expected 'owner' or 'owner/repo'", target)
+		}
+		if _, dup := tokens[target]; dup {
+			return nil, fmt.Errorf("duplicate token target %q", target)
+		}
+		tokens[target] = token
+	}
+	return tokens, nil
+}
+
+// For returns the token for owner/repo, preferring a repository entry over an
+// owner entry and falling back to fallback. repo may be empty for org or user
+// wide targets.
+func (t Tokens) For(owner, repo, fallback string) string {
+	owner = strings.ToLower(owner)
+	if repo != "" {
+		if token, ok := t[owner+"/"+strings.ToLower(repo)]; ok {
+			return token
+		}
+	}
+	if token, ok := t[owner]; ok {
+		return token
+	}
+	return fallback
+}
+
+// QueryTarget returns the owner and repository a search query is scoped to by
+// its first repo:, org: or user: qualifier. repo is empty for org and user
+// qualifiers and ok is false when the query has none.
+func QueryTarget(query string) (owner, repo string, ok bool) {
+	for _, field := range strings.Fields(query) {
+		if value, found := strings.CutPrefix(field, "repo:"); found {
+			owner, repo, _ = strings.Cut(value, "/")
+			return owner, repo, true
+		}
+		for _, qualifier := range []string{"org:", "user:"} {
+			if value, found := strings.CutPrefix(field, qualifier); found {
+				return value, "", true
+			}
+		}
+	}
+	return "", "", false
+}
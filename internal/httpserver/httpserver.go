@@ -0,0 +1,34 @@
+package httpserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gitnotifier/config"
+	"log/slog"
+	"net/http"
+)
+
+// Run serves handler on addr until ctx is cancelled, then shuts down, giving
+// in-flight requests config.ServerShutdownTimeout to finish. name describes
+// the server in errors, e.g. "REST API", and event tags its logs.
+func Run(ctx context.Context, addr string, handler http.Handler, name, event string) error {
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: config.ServerHeaderTimeout,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), config.ServerShutdownTimeout)
+		defer cancel()
+		srv.Shutdown(shutdownCtx)
+	}()
+
+	slog.Info("Serving "+name, "event", event, "addr", addr)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("error serving %s: %v", name, err)
+	}
+	return nil
+}
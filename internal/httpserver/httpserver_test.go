@@ -0,0 +1,39 @@
+package httpserver
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunStopsWhenContextIsCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- Run(ctx, "127.0.0.1:0", http.NotFoundHandler(), "test server", "test") }()
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Run = %v, want nil after cancellation", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run didn't return after the context was cancelled")
+	}
+}
+
+func TestRunReportsListenErrors(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	err = Run(context.Background(), l.Addr().String(), http.NotFoundHandler(), "test server", "test")
+	if err == nil || !strings.Contains(err.Error(), "error serving test server") {
+		t.Errorf("Run on an address in use = %v, want an error naming the server", err)
+	}
+}
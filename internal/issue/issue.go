@@ -1,19 +1,53 @@
 package issue
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 // PullRequest represents the pull_request field in GitHub's API
 type PullRequest struct {
 	URL string `json:"url"`
 }
 
+// User represents a GitHub account referenced by an issue
+type User struct {
+	Login string `json:"login"`
+}
+
+// Label represents a label attached to an issue
+type Label struct {
+	Name string `json:"name"`
+}
+
 // Issue represents a GitHub issue
 type Issue struct {
-	ID          int          `json:"id"`
-	Number      int          `json:"number"`
-	Title       string       `json:"title"`
-	CreatedAt   time.Time    `json:"created_at"`
-	HTMLURL     string       `json:"html_url"`
-	State       string       `json:"state"`
-	PullRequest *PullRequest `json:"pull_request,omitempty"`
+	ID            int          `json:"id"`
+	Number        int          `json:"number"`
+	URL           string       `json:"url"` // API URL of the issue
+	Title         string       `json:"title"`
+	Body          string       `json:"body"`
+	CreatedAt     time.Time    `json:"created_at"`
+	HTMLURL       string       `json:"html_url"`
+	RepositoryURL string       `json:"repository_url"`
+	State         string       `json:"state"`
+	PullRequest   *PullRequest `json:"pull_request,omitempty"`
+	User          User         `json:"user"` // Author of the issue
+	Assignees     []User       `json:"assignees"`
+	Labels        []Label      `json:"labels"`
+	// LinkedPullRequests is filled in by the optional linked PR lookup, not by the API
+	LinkedPullRequests []LinkedPullRequest `json:"-"`
+}
+
+// RepoFullName returns the "owner/repo" of the issue's repository, or an
+// empty string when RepositoryURL isn't set
+func (i Issue) RepoFullName() string {
+	_, name, _ := strings.Cut(i.RepositoryURL, "/repos/")
+	return name
+}
+
+// LinkedPullRequest is a pull request that references an issue
+type LinkedPullRequest struct {
+	Number int
+	State  string // "open", "closed" or "merged"
 }
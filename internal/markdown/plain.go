@@ -0,0 +1,74 @@
+package markdown
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+var parser = goldmark.New().Parser()
+
+// ToPlainText renders markdown as readable plain text: emphasis and code
+// markers are dropped, links become "text (url)" and images their alt text.
+func ToPlainText(src string) string {
+	source := []byte(src)
+	doc := parser.Parse(text.NewReader(source))
+
+	var buf bytes.Buffer
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		switch node := n.(type) {
+		case *ast.Text:
+			if entering {
+				buf.Write(node.Segment.Value(source))
+				if node.HardLineBreak() {
+					buf.WriteByte('\n')
+				} else if node.SoftLineBreak() {
+					buf.WriteByte(' ')
+				}
+			}
+		case *ast.String:
+			if entering {
+				buf.Write(node.Value)
+			}
+		case *ast.AutoLink:
+			if entering {
+				buf.Write(node.URL(source))
+			}
+		case *ast.Link:
+			if !entering {
+				dest := string(node.Destination)
+				if dest != "" && !strings.HasSuffix(buf.String(), dest) {
+					buf.WriteString(" (" + dest + ")")
+				}
+			}
+		case *ast.FencedCodeBlock, *ast.CodeBlock:
+			if entering {
+				lines := n.Lines()
+				for i := 0; i < lines.Len(); i++ {
+					line := lines.At(i)
+					buf.Write(line.Value(source))
+				}
+				return ast.WalkSkipChildren, nil
+			}
+		case *ast.RawHTML, *ast.HTMLBlock:
+			return ast.WalkSkipChildren, nil
+		case *ast.ListItem:
+			if entering {
+				buf.WriteString("- ")
+			}
+		}
+
+		// Separate block elements with a newline
+		if !entering && n.Type() == ast.TypeBlock && n.Kind() != ast.KindDocument {
+			if buf.Len() > 0 && !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+				buf.WriteByte('\n')
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+
+	return strings.TrimSpace(buf.String())
+}
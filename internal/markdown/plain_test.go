@@ -0,0 +1,33 @@
+package markdown
+
+import "testing"
+
+func TestToPlainText(t *testing.T) {
+	tests := []struct {
+		name, markdown, want string
+	}{
+		{"plain text", "Crash on start", "Crash on start"},
+		{"emphasis", "**bold**, *em* and __strong__", "bold, em and strong"},
+		{"inline code", "`nil` pointer in `main`", "nil pointer in main"},
+		{"link", "See [the docs](https://example.com/docs)", "See the docs (https://example.com/docs)"},
+		{"link text is the url", "[https://example.com](https://example.com)", "https://example.com"},
+		{"autolink", "<https://example.com>", "https://example.com"},
+		{"image", "![screenshot](shot.png)", "screenshot"},
+		{"heading and paragraph", "# Steps\n\nRun it twice", "Steps\nRun it twice"},
+		{"list", "- one\n- two", "- one\n- two"},
+		{"fenced code", "```go\npanic(err)\n```", "panic(err)"},
+		{"inline html", "a <b>bold</b> word", "a bold word"},
+		{"html block", "<details>\nlogs\n</details>\n\nSummary", "Summary"},
+		{"blockquote", "> it broke", "it broke"},
+		{"soft line break", "first\nsecond", "first second"},
+		{"hard line break", "first  \nsecond", "first\nsecond"},
+		{"empty", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ToPlainText(tt.markdown); got != tt.want {
+				t.Errorf("ToPlainText(%q) = %q, want %q", tt.markdown, got, tt.want)
+			}
+		})
+	}
+}
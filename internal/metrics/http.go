@@ -0,0 +1,64 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"gitnotifier/internal/httpserver"
+	"net/http"
+	"strings"
+)
+
+// HealthServer serves /healthz for liveness checks and /metrics with the
+// counters in the Prometheus text format
+type HealthServer struct {
+	metrics *Metrics
+	health  func() error
+}
+
+// NewHealthServer creates a HealthServer. health reports the outcome of the
+// most recent polls and is nil when they succeeded.
+func NewHealthServer(m *Metrics, health func() error) *HealthServer {
+	return &HealthServer{metrics: m, health: health}
+}
+
+// Handler returns the /healthz and /metrics routes
+func (s *HealthServer) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", s.handleHealth)
+	mux.HandleFunc("GET /metrics", s.handleMetrics)
+	return mux
+}
+
+// Run serves the endpoints on addr until ctx is cancelled
+func (s *HealthServer) Run(ctx context.Context, addr string) error {
+	return httpserver.Run(ctx, addr, s.Handler(), "health endpoints", "health")
+}
+
+func (s *HealthServer) handleHealth(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if err := s.health(); err != nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprintf(w, "unhealthy: %v\n", err)
+		return
+	}
+	fmt.Fprintln(w, "ok")
+}
+
+func (s *HealthServer) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	m := s.metrics
+	var sb strings.Builder
+	metric := func(name, kind, help string, value int64) {
+		fmt.Fprintf(&sb, "# HELP gitnotifier_%s %s\n# TYPE gitnotifier_%s %s\ngitnotifier_%s %d\n",
+			name, help, name, kind, name, value)
+	}
+	metric("polls_total", "counter", "Completed polls.", m.Polls.Load())
+	metric("poll_failures_total", "counter", "Polls that ended in an error.", m.PollFailures.Load())
+	metric("notifications_sent_total", "counter", "Notifications delivered.", m.NotificationsSent.Load())
+	metric("notification_failures_total", "counter", "Notifications that failed to deliver.", m.NotificationFailures.Load())
+	metric("panics_total", "counter", "Polls that panicked.", m.Panics.Load())
+	metric("last_poll_timestamp_seconds", "gauge", "Unix time of the last completed poll, 0 if none.", m.lastPoll.Load())
+	metric("rate_limit_remaining", "gauge", "Last reported GitHub rate-limit remaining, -1 if unknown.", m.RateLimitRemaining.Load())
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	fmt.Fprint(w, sb.String())
+}
@@ -0,0 +1,42 @@
+package metrics
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Metrics holds the service counters shared by all exporters
+type Metrics struct {
+	Polls                atomic.Int64
+	PollFailures         atomic.Int64
+	NotificationsSent    atomic.Int64
+	NotificationFailures atomic.Int64
+	Panics               atomic.Int64
+	// RateLimitRemaining is the last reported GitHub rate-limit remaining, -1 if unknown
+	RateLimitRemaining atomic.Int64
+	lastPoll           atomic.Int64 // Unix seconds of the last completed poll
+}
+
+// New creates a Metrics with no recorded activity
+func New() *Metrics {
+	m := &Metrics{}
+	m.RateLimitRemaining.Store(-1)
+	return m
+}
+
+// RecordPoll counts a completed poll and whether it failed
+func (m *Metrics) RecordPoll(err error) {
+	m.Polls.Add(1)
+	if err != nil {
+		m.PollFailures.Add(1)
+	}
+	m.lastPoll.Store(time.Now().Unix())
+}
+
+// LastPoll returns when the last poll completed, or the zero time if none has
+func (m *Metrics) LastPoll() time.Time {
+	if ts := m.lastPoll.Load(); ts > 0 {
+		return time.Unix(ts, 0)
+	}
+	return time.Time{}
+}
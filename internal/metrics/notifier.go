@@ -0,0 +1,24 @@
+package metrics
+
+import "gitnotifier/internal/notifier"
+
+// countingNotifier counts delivered and failed notifications
+type countingNotifier struct {
+	notifier notifier.Notifier
+	metrics  *Metrics
+}
+
+// CountNotifications wraps n so every notification is counted in m
+func CountNotifications(n notifier.Notifier, m *Metrics) notifier.Notifier {
+	return &countingNotifier{notifier: n, metrics: m}
+}
+
+func (c *countingNotifier) Notify(title, message, url string) error {
+	err := c.notifier.Notify(title, message, url)
+	if err != nil {
+		c.metrics.NotificationFailures.Add(1)
+	} else {
+		c.metrics.NotificationsSent.Add(1)
+	}
+	return err
+}
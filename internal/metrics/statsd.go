@@ -0,0 +1,79 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"strings"
+	"time"
+)
+
+// StatsD periodically sends metrics to a StatsD server over UDP. Counters are
+// sent as deltas since the previous flush and the rate limit as a gauge.
+type StatsD struct {
+	metrics  *Metrics
+	addr     string
+	prefix   string
+	interval time.Duration
+	last     map[string]int64
+}
+
+// NewStatsD creates a StatsD exporter for addr ("host:port") with names under prefix
+func NewStatsD(m *Metrics, addr, prefix string, interval time.Duration) *StatsD {
+	return &StatsD{
+		metrics:  m,
+		addr:     addr,
+		prefix:   strings.TrimSuffix(prefix, "."),
+		interval: interval,
+		last:     make(map[string]int64),
+	}
+}
+
+// Run flushes metrics every interval until ctx is cancelled
+func (s *StatsD) Run(ctx context.Context) error {
+	conn, err := net.Dial("udp", s.addr)
+	if err != nil {
+		return fmt.Errorf("error connecting to StatsD at %s: %v", s.addr, err)
+	}
+	defer conn.Close()
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			// UDP is fire-and-forget, so a missing server only shows up as write errors
+			if _, err := conn.Write([]byte(s.payload())); err != nil {
+				slog.Error("Error sending metrics to StatsD", "event", "statsd", "error", err)
+			}
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// payload renders the current metrics as newline-separated StatsD lines
+func (s *StatsD) payload() string {
+	var lines []string
+	counters := []struct {
+		name  string
+		value int64
+	}{
+		{"polls", s.metrics.Polls.Load()},
+		{"poll_failures", s.metrics.PollFailures.Load()},
+		{"notifications_sent", s.metrics.NotificationsSent.Load()},
+		{"notification_failures", s.metrics.NotificationFailures.Load()},
+		{"panics", s.metrics.Panics.Load()},
+	}
+	for _, c := range counters {
+		lines = append(lines, fmt.Sprintf("%s.%s:%d|c", s.prefix, c.name, c.value-s.last[c.name]))
+		s.last[c.name] = c.value
+	}
+
+	if remaining := s.metrics.RateLimitRemaining.Load(); remaining >= 0 {
+		lines = append(lines, fmt.Sprintf("%s.rate_limit_remaining:%d|g", s.prefix, remaining))
+	}
+	return strings.Join(lines, "\n")
+}
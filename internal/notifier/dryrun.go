@@ -0,0 +1,17 @@
+package notifier
+
+import "log/slog"
+
+// DryRunNotifier logs the notifications it would have sent instead of
+// delivering them, which makes it safe to try filters and templates
+type DryRunNotifier struct{}
+
+// NewDryRunNotifier creates a DryRunNotifier
+func NewDryRunNotifier() *DryRunNotifier {
+	return &DryRunNotifier{}
+}
+
+func (n *DryRunNotifier) Notify(title, message, url string) error {
+	slog.Info("[dry run] Would notify", "event", "notification", "title", title, "message", message, "url", url)
+	return nil
+}
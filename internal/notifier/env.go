@@ -0,0 +1,142 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"gitnotifier/config"
+	"gitnotifier/internal/notifier/platform"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// FromEnv creates the notification channels configured in the environment:
+// the console in tail mode, syslog, a user command, an SNS topic, Slack,
+// Discord, email and/or a Matrix room, plus the platform-specific desktop
+// notifier when NOTIFIER includes desktop or nothing else is configured.
+// Several channels are combined into a MultiNotifier, and the result is
+// wrapped in a TemplateNotifier when NOTIFY_FORMAT is custom-template. With DRY_RUN
+// no channel is set up and notifications are only logged. open, which may be
+// nil, is counted by a desktop notification updated in place. Webhook
+// channels send through client and stop waiting out rate limits once ctx is done.
+func FromEnv(ctx context.Context, client *http.Client, tail bool, open *OpenIssues, icons func() platform.Icons) (Notifier, error) {
+	n, err := channelsFromEnv(ctx, client, tail, open, icons)
+	if err != nil {
+		return nil, err
+	}
+
+	// Templates applied uniformly regardless of channel, with NOTIFY_FORMAT=custom-template
+	format, err := ParseFormat(os.Getenv("NOTIFY_FORMAT"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid NOTIFY_FORMAT: %v", err)
+	}
+	titleTemplate, messageTemplate := os.Getenv("NOTIFY_TITLE_TEMPLATE"), os.Getenv("NOTIFY_MESSAGE_TEMPLATE")
+	if format != FormatCustomTemplate {
+		if titleTemplate != "" || messageTemplate != "" {
+			slog.Warn("Ignoring NOTIFY_TITLE_TEMPLATE and NOTIFY_MESSAGE_TEMPLATE, which need NOTIFY_FORMAT=custom-template", "format", string(format))
+		}
+		return n, nil
+	}
+	return NewTemplateNotifier(n, titleTemplate, messageTemplate)
+}
+
+func channelsFromEnv(ctx context.Context, client *http.Client, tail bool, open *OpenIssues, icons func() platform.Icons) (Notifier, error) {
+	if dryRun, _ := strconv.ParseBool(os.Getenv("DRY_RUN")); dryRun {
+		slog.Info("Dry run: notifications are logged, not sent")
+		return NewDryRunNotifier(), nil
+	}
+
+	var channels []NamedNotifier
+	if tail {
+		channels = append(channels, NamedNotifier{Name: "console", Notifier: platform.NewConsoleNotifier()})
+	}
+
+	// NOTIFIER lists the built-in channels; desktop is also used when nothing else is configured
+	desktop := false
+	for _, kind := range config.SplitList(os.Getenv("NOTIFIER")) {
+		switch kind {
+		case "desktop":
+			desktop = true
+		case "syslog":
+			tag := os.Getenv("SYSLOG_TAG")
+			if tag == "" {
+				tag = "gitnotifier"
+			}
+			syslog, err := platform.NewSyslogNotifier(tag, os.Getenv("SYSLOG_FACILITY"), os.Getenv("SYSLOG_SEVERITY"))
+			if err != nil {
+				return nil, fmt.Errorf("error initializing syslog notifier: %v", err)
+			}
+			channels = append(channels, NamedNotifier{Name: "syslog", Notifier: syslog})
+		default:
+			return nil, fmt.Errorf("unknown NOTIFIER %q. Expected 'desktop' and/or 'syslog'", kind)
+		}
+	}
+	if execCommand := strings.Fields(os.Getenv("EXEC_COMMAND")); len(execCommand) > 0 {
+		channels = append(channels, NamedNotifier{
+			Name:     "exec",
+			Notifier: platform.NewExecNotifier(execCommand[0], execCommand[1:], config.ExecTimeout),
+		})
+	}
+	if topicARN := os.Getenv("SNS_TOPIC_ARN"); topicARN != "" {
+		sns, err := platform.NewSNSNotifier(context.Background(), topicARN, config.HTTPTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("error initializing SNS notifier: %v", err)
+		}
+		channels = append(channels, NamedNotifier{Name: "sns", Notifier: sns})
+	}
+	if webhookURL := os.Getenv("SLACK_WEBHOOK_URL"); webhookURL != "" {
+		slack := platform.NewSlackNotifier(client, webhookURL)
+		channels = append(channels, NamedNotifier{Name: "slack", Notifier: slack})
+	}
+	if webhookURL := os.Getenv("DISCORD_WEBHOOK_URL"); webhookURL != "" {
+		discord := platform.NewDiscordNotifier(ctx, client, webhookURL, config.MaxRetries)
+		channels = append(channels, NamedNotifier{Name: "discord", Notifier: discord})
+	}
+	if host := os.Getenv("SMTP_HOST"); host != "" {
+		port, _ := strconv.Atoi(os.Getenv("SMTP_PORT"))
+		email, err := platform.NewEmailNotifier(host, port, os.Getenv("SMTP_USER"), os.Getenv("SMTP_PASS"),
+			os.Getenv("SMTP_FROM"), config.SplitList(os.Getenv("SMTP_TO")), config.HTTPTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("error initializing email notifier: %v", err)
+		}
+		channels = append(channels, NamedNotifier{Name: "email", Notifier: email})
+	}
+	if homeserver := os.Getenv("MATRIX_HOMESERVER"); homeserver != "" {
+		matrix, err := platform.NewMatrixNotifier(ctx, client, homeserver,
+			os.Getenv("MATRIX_ACCESS_TOKEN"), os.Getenv("MATRIX_ROOM_ID"), config.MaxRetries)
+		if err != nil {
+			return nil, fmt.Errorf("error initializing Matrix notifier: %v", err)
+		}
+		channels = append(channels, NamedNotifier{Name: "matrix", Notifier: matrix})
+	}
+
+	if desktop || len(channels) == 0 {
+		var desktopIcons platform.Icons
+		if avatars, err := strconv.ParseBool(os.Getenv("NOTIFY_AVATAR_ICON")); err != nil || avatars {
+			desktopIcons = icons()
+		}
+		sound := os.Getenv("NOTIFY_SOUND")
+		if silent, _ := strconv.ParseBool(os.Getenv("NOTIFY_SILENT")); silent {
+			sound = platform.SoundNone
+		}
+		platformNotifier, err := NewPlatformNotifier(desktopIcons, sound)
+		if err != nil {
+			return nil, err
+		}
+		if open != nil {
+			platformNotifier = NewReplaceInPlaceNotifier(platformNotifier, open, desktopIcons, sound)
+		}
+		channels = append(channels, NamedNotifier{Name: "desktop", Notifier: platformNotifier})
+	}
+
+	var n Notifier
+	if len(channels) == 1 {
+		n = channels[0].Notifier
+	} else {
+		concurrency, _ := strconv.Atoi(os.Getenv("NOTIFY_CONCURRENCY"))
+		n = NewMultiNotifier(channels, concurrency)
+	}
+	return n, nil
+}
@@ -0,0 +1,92 @@
+package notifier
+
+import (
+	"context"
+	"gitnotifier/internal/issue"
+	"gitnotifier/internal/notifier/platform"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFromEnv(t *testing.T) {
+	noIcons := func() platform.Icons { return nil }
+
+	t.Setenv("DRY_RUN", "true")
+	n, err := FromEnv(context.Background(), http.DefaultClient, false, nil, noIcons)
+	if _, ok := n.(*DryRunNotifier); err != nil || !ok {
+		t.Errorf("FromEnv with DRY_RUN = %T, %v, want a DryRunNotifier", n, err)
+	}
+
+	t.Setenv("DRY_RUN", "false")
+	t.Setenv("SLACK_WEBHOOK_URL", "https://hooks.slack.com/services/x")
+	t.Setenv("DISCORD_WEBHOOK_URL", "https://discord.com/api/webhooks/x")
+	n, err = FromEnv(context.Background(), http.DefaultClient, false, nil, noIcons)
+	if _, ok := n.(*MultiNotifier); err != nil || !ok {
+		t.Errorf("FromEnv with two webhooks = %T, %v, want a MultiNotifier", n, err)
+	}
+
+	t.Setenv("NOTIFY_TITLE_TEMPLATE", "[gh] {{.Title}}")
+	n, err = FromEnv(context.Background(), http.DefaultClient, false, nil, noIcons)
+	if _, ok := n.(*MultiNotifier); err != nil || !ok {
+		t.Errorf("FromEnv with a title template and the full format = %T, %v, want a MultiNotifier", n, err)
+	}
+
+	t.Setenv("NOTIFY_FORMAT", "custom-template")
+	n, err = FromEnv(context.Background(), http.DefaultClient, false, nil, noIcons)
+	if _, ok := n.(*TemplateNotifier); err != nil || !ok {
+		t.Errorf("FromEnv with a title template and the custom-template format = %T, %v, want a TemplateNotifier", n, err)
+	}
+
+	t.Setenv("NOTIFIER", "pager")
+	if _, err := FromEnv(context.Background(), http.DefaultClient, false, nil, noIcons); err == nil {
+		t.Error("FromEnv accepted an unknown NOTIFIER")
+	}
+}
+
+func TestFromEnvTemplatesNeedCustomFormat(t *testing.T) {
+	var mu sync.Mutex
+	var body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		body = string(b)
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		format    Format
+		rewritten bool
+	}{
+		{FormatFull, false},
+		{FormatCompact, false},
+		{FormatCustomTemplate, true},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			t.Setenv("SLACK_WEBHOOK_URL", srv.URL)
+			t.Setenv("NOTIFY_FORMAT", string(tt.format))
+			t.Setenv("NOTIFY_TITLE_TEMPLATE", "[gh] {{.Title}}")
+			n, err := FromEnv(context.Background(), &http.Client{Timeout: 5 * time.Second}, false, nil, func() platform.Icons { return nil })
+			if err != nil {
+				t.Fatalf("FromEnv: %v", err)
+			}
+			in := NewIssueNotifier(n)
+			in.Format = tt.format
+			if err := in.NotifyNewIssue(issue.Issue{Number: 42, Title: "Crash on start", HTMLURL: "https://github.com/owner/repo/issues/42"}); err != nil {
+				t.Fatalf("NotifyNewIssue: %v", err)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if got := strings.Contains(body, "[gh]"); got != tt.rewritten {
+				t.Errorf("title rewritten = %v, want %v; sent %s", got, tt.rewritten, body)
+			}
+		})
+	}
+}
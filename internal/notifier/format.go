@@ -0,0 +1,27 @@
+package notifier
+
+import "fmt"
+
+// Format selects how new-issue notifications are laid out
+type Format string
+
+const (
+	// FormatFull is the default "New GitHub Issue" / "#42 by alice: Title" layout
+	FormatFull Format = "full"
+	// FormatCompact sends only "#42" with the URL, for small screens
+	FormatCompact Format = "compact"
+	// FormatCustomTemplate uses the full layout rewritten by a TemplateNotifier
+	FormatCustomTemplate Format = "custom-template"
+)
+
+// ParseFormat parses a format name, defaulting to FormatFull when empty
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(s); f {
+	case "":
+		return FormatFull, nil
+	case FormatFull, FormatCompact, FormatCustomTemplate:
+		return f, nil
+	default:
+		return "", fmt.Errorf("unknown notification format %q. Expected full, compact or custom-template", s)
+	}
+}
@@ -0,0 +1,77 @@
+package notifier
+
+import (
+	"gitnotifier/internal/issue"
+	"testing"
+)
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Format
+		wantErr bool
+	}{
+		{"", FormatFull, false},
+		{"full", FormatFull, false},
+		{"compact", FormatCompact, false},
+		{"custom-template", FormatCustomTemplate, false},
+		{"Compact", "", true},
+		{"short", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseFormat(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseFormat(%q) = %q, %v; want %q, error %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestNotifyNewIssueFormats(t *testing.T) {
+	crash := issue.Issue{
+		ID:      142,
+		Number:  42,
+		Title:   "Crash on start",
+		HTMLURL: "https://github.com/owner/repo/issues/42",
+		User:    issue.User{Login: "alice"},
+	}
+	pr := crash
+	pr.PullRequest = &issue.PullRequest{}
+
+	tests := []struct {
+		name               string
+		format             Format
+		issue              issue.Issue
+		title              string // Title template for the TemplateNotifier, if any
+		wantTitle, wantMsg string
+	}{
+		{"full", FormatFull, crash, "", "New GitHub Issue", "#42 by alice: Crash on start"},
+		{"zero value is full", "", crash, "", "New GitHub Issue", "#42 by alice: Crash on start"},
+		{"full pull request", FormatFull, pr, "", "New GitHub Pull Request", "#42 by alice: Crash on start"},
+		{"compact", FormatCompact, crash, "", "#42", ""},
+		{"compact pull request", FormatCompact, pr, "", "PR #42", ""},
+		{"custom-template", FormatCustomTemplate, crash, "{{.Message}}", "#42 by alice: Crash on start", "#42 by alice: Crash on start"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := &recordingNotifier{}
+			var n Notifier = rec
+			if tt.title != "" {
+				tn, err := NewTemplateNotifier(rec, tt.title, "")
+				if err != nil {
+					t.Fatalf("NewTemplateNotifier: %v", err)
+				}
+				n = tn
+			}
+			in := NewIssueNotifier(n)
+			in.Format = tt.format
+
+			if err := in.NotifyNewIssue(tt.issue); err != nil {
+				t.Fatalf("NotifyNewIssue: %v", err)
+			}
+			got := rec.last(t)
+			if got.Title != tt.wantTitle || got.Message != tt.wantMsg || got.URL != tt.issue.HTMLURL {
+				t.Errorf("sent %+v, want title %q, message %q and the issue URL", got, tt.wantTitle, tt.wantMsg)
+			}
+		})
+	}
+}
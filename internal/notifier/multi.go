@@ -0,0 +1,109 @@
+package notifier
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// NamedNotifier pairs a notifier with the channel name used in error reports
+type NamedNotifier struct {
+	Name     string
+	Notifier Notifier
+}
+
+// MultiNotifier fans a notification out to several channels concurrently
+type MultiNotifier struct {
+	channels    []NamedNotifier
+	concurrency int
+}
+
+// NewMultiNotifier creates a MultiNotifier that sends to at most concurrency
+// channels at once. A concurrency of zero or less sends to all channels at once.
+func NewMultiNotifier(channels []NamedNotifier, concurrency int) *MultiNotifier {
+	if concurrency <= 0 || concurrency > len(channels) {
+		concurrency = len(channels)
+	}
+	return &MultiNotifier{
+		channels:    channels,
+		concurrency: concurrency,
+	}
+}
+
+// PartialError is returned by MultiNotifier when the notification reached
+// some channels but not others
+type PartialError struct {
+	err    error // The failures joined in channel order
+	retry  func() error
+	failed []string
+}
+
+func (e *PartialError) Error() string { return e.err.Error() }
+
+func (e *PartialError) Unwrap() error { return e.err }
+
+// Failed returns the names of the channels the notification didn't reach
+func (e *PartialError) Failed() []string { return e.failed }
+
+// Retry sends the notification again to the channels that failed only. It
+// returns a PartialError for the channels still failing.
+func (e *PartialError) Retry() error { return e.retry() }
+
+// IsPartial reports whether err means a notification reached at least one channel
+func IsPartial(err error) bool {
+	var partial *PartialError
+	return errors.As(err, &partial)
+}
+
+// Notify sends to every channel, even when some fail. The returned error joins
+// the failures in channel order, each prefixed with the channel name, and is a
+// *PartialError if any channel succeeded.
+func (m *MultiNotifier) Notify(title, message, url string) error {
+	return m.send(m.channels, false, title, message, url)
+}
+
+// send delivers to channels. delivered is whether an earlier attempt already
+// reached another channel, so that a retry failing everywhere is still partial.
+func (m *MultiNotifier) send(channels []NamedNotifier, delivered bool, title, message, url string) error {
+	errs := make([]error, len(channels))
+	sem := make(chan struct{}, m.concurrency)
+
+	var wg sync.WaitGroup
+	for i, ch := range channels {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, ch NamedNotifier) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if err := ch.Notifier.Notify(title, message, url); err != nil {
+				errs[i] = fmt.Errorf("%s: %w", ch.Name, err)
+			}
+		}(i, ch)
+	}
+	wg.Wait()
+
+	var failed []NamedNotifier
+	for i, err := range errs {
+		if err != nil {
+			failed = append(failed, channels[i])
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	err := errors.Join(errs...)
+	if !delivered && len(failed) == len(channels) {
+		return err
+	}
+
+	names := make([]string, len(failed))
+	for i, ch := range failed {
+		names[i] = ch.Name
+	}
+	return &PartialError{
+		err:    err,
+		failed: names,
+		retry:  func() error { return m.send(failed, true, title, message, url) },
+	}
+}
@@ -0,0 +1,157 @@
+package notifier
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// funcNotifier adapts a function to Notifier
+type funcNotifier func(title, message, url string) error
+
+func (f funcNotifier) Notify(title, message, url string) error {
+	return f(title, message, url)
+}
+
+func TestMultiNotifierSendsToAllChannelsWhenOneFails(t *testing.T) {
+	desktop, email := &recordingNotifier{}, &recordingNotifier{}
+	// The first failure is slower than the second, so the error order can
+	// only follow the channel order
+	slack := funcNotifier(func(title, message, url string) error {
+		time.Sleep(20 * time.Millisecond)
+		return errors.New("webhook returned 500")
+	})
+	discord := funcNotifier(func(title, message, url string) error {
+		return errors.New("rate limited")
+	})
+
+	m := NewMultiNotifier([]NamedNotifier{
+		{Name: "desktop", Notifier: desktop},
+		{Name: "slack", Notifier: slack},
+		{Name: "email", Notifier: email},
+		{Name: "discord", Notifier: discord},
+	}, 0)
+
+	err := m.Notify("New GitHub Issue", "#42: Crash", "https://github.com/owner/repo/issues/42")
+	if err == nil {
+		t.Fatal("Notify succeeded, want the slack and discord failures")
+	}
+	for _, rec := range []*recordingNotifier{desktop, email} {
+		if got := rec.last(t); got.URL != "https://github.com/owner/repo/issues/42" {
+			t.Errorf("channel got %+v", got)
+		}
+	}
+	if want := "slack: webhook returned 500\ndiscord: rate limited"; err.Error() != want {
+		t.Errorf("error = %q, want %q", err, want)
+	}
+}
+
+func TestMultiNotifierSucceeds(t *testing.T) {
+	a, b := &recordingNotifier{}, &recordingNotifier{}
+	m := NewMultiNotifier([]NamedNotifier{{Name: "a", Notifier: a}, {Name: "b", Notifier: b}}, 1)
+
+	if err := m.Notify("title", "message", "url"); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	if len(a.sent) != 1 || len(b.sent) != 1 {
+		t.Errorf("sent %d and %d notifications, want one each", len(a.sent), len(b.sent))
+	}
+}
+
+func TestMultiNotifierConcurrency(t *testing.T) {
+	tests := []struct {
+		concurrency int
+		want        int
+	}{
+		{1, 1},
+		{2, 2},
+		{0, 5},
+		{10, 5},
+	}
+	for _, tt := range tests {
+		var mu sync.Mutex
+		running, peak := 0, 0
+		slow := funcNotifier(func(title, message, url string) error {
+			mu.Lock()
+			running++
+			peak = max(peak, running)
+			mu.Unlock()
+
+			time.Sleep(20 * time.Millisecond)
+
+			mu.Lock()
+			running--
+			mu.Unlock()
+			return nil
+		})
+
+		channels := make([]NamedNotifier, 5)
+		for i := range channels {
+			channels[i] = NamedNotifier{Name: strings.Repeat("x", i+1), Notifier: slow}
+		}
+		if err := NewMultiNotifier(channels, tt.concurrency).Notify("title", "message", "url"); err != nil {
+			t.Fatalf("Notify: %v", err)
+		}
+		if peak != tt.want {
+			t.Errorf("concurrency %d: %d channels ran at once, want %d", tt.concurrency, peak, tt.want)
+		}
+	}
+}
+
+// flakyNotifier fails its first failures notifications
+type flakyNotifier struct {
+	recordingNotifier
+	failures int
+}
+
+func (f *flakyNotifier) Notify(title, message, url string) error {
+	if f.failures > 0 {
+		f.failures--
+		return errors.New("connection reset")
+	}
+	return f.recordingNotifier.Notify(title, message, url)
+}
+
+func TestMultiNotifierRetriesOnlyFailedChannels(t *testing.T) {
+	desktop, slack, email := &recordingNotifier{}, &flakyNotifier{failures: 2}, &flakyNotifier{failures: 2}
+	m := NewMultiNotifier([]NamedNotifier{
+		{Name: "desktop", Notifier: desktop},
+		{Name: "slack", Notifier: slack},
+		{Name: "email", Notifier: email},
+	}, 0)
+
+	err := m.Notify("title", "message", "url")
+	var partial *PartialError
+	if !errors.As(err, &partial) {
+		t.Fatalf("Notify = %v, want a PartialError", err)
+	}
+	if got := partial.Failed(); !slices.Equal(got, []string{"slack", "email"}) {
+		t.Errorf("failed channels = %v, want slack and email", got)
+	}
+
+	// Still partial when every retried channel fails again
+	if err = partial.Retry(); !errors.As(err, &partial) || !slices.Equal(partial.Failed(), []string{"slack", "email"}) {
+		t.Fatalf("first Retry = %v, want a PartialError for slack and email", err)
+	}
+	if err = partial.Retry(); err != nil {
+		t.Fatalf("second Retry = %v, want nil", err)
+	}
+	for name, sent := range map[string]int{"desktop": len(desktop.sent), "slack": len(slack.sent), "email": len(email.sent)} {
+		if sent != 1 {
+			t.Errorf("%s got %d notifications, want exactly one", name, sent)
+		}
+	}
+}
+
+func TestMultiNotifierAllChannelsFail(t *testing.T) {
+	fail := funcNotifier(func(title, message, url string) error { return errors.New("down") })
+	m := NewMultiNotifier([]NamedNotifier{{Name: "a", Notifier: fail}, {Name: "b", Notifier: fail}}, 0)
+
+	err := m.Notify("title", "message", "url")
+	if err == nil || IsPartial(err) {
+		t.Errorf("Notify = %#v, want an error that isn't partial", err)
+	}
+}
@@ -1,10 +1,18 @@
 package notifier
 
 import (
+	"errors"
 	"fmt"
+	"gitnotifier/config"
 	"gitnotifier/internal/issue"
+	"gitnotifier/internal/markdown"
 	"gitnotifier/internal/notifier/platform"
+	"gitnotifier/internal/repository"
+	"log/slog"
 	"runtime"
+	"strings"
+	"text/template"
+	"time"
 )
 
 // NotificationMessage represents a notification to be sent
@@ -14,9 +22,32 @@ type NotificationMessage struct {
 	URL     string
 }
 
+// ErrAlreadySent is returned by IssueNotifier.NotifyNewIssue for an issue that
+// Sent shows was notified before a restart, so nothing was sent this time
+var ErrAlreadySent = errors.New("issue was already notified")
+
+// SentLog remembers which issues were notified, across restarts
+type SentLog interface {
+	Seen(id int) bool
+	Record(id int) error
+}
+
 // IssueNotifier converts issues to notification messages
 type IssueNotifier struct {
 	notifier Notifier
+	// Tag, when set, prefixes notification titles, e.g. "[triage] New GitHub Issue"
+	Tag string
+	// RenderMarkdown converts markdown in issue text to plain text
+	RenderMarkdown bool
+	// Format selects the new-issue layout; the zero value behaves as FormatFull
+	Format Format
+	// TagWithRepo tags issue notifications with the issue's owner/repo instead
+	// of Tag, for views that span several repositories
+	TagWithRepo bool
+	// Sent, when set, skips new issues notified before a restart and records sent ones
+	Sent SentLog
+	// MessageTemplate, when set, replaces the "#42 by alice: Title" new-issue message
+	MessageTemplate *template.Template
 }
 
 // NewIssueNotifier creates a new IssueNotifier
@@ -26,26 +57,264 @@ func NewIssueNotifier(notifier Notifier) *IssueNotifier {
 	}
 }
 
-// NotifyNewIssue sends a notification for a new issue
+// NotifyNewIssue sends a notification for a new issue, or for a new pull
+// request when pull requests are watched. Issues already in Sent are skipped
+// with ErrAlreadySent.
 func (in *IssueNotifier) NotifyNewIssue(issue issue.Issue) error {
-	title := "New GitHub Issue"
-	message := formatIssueMessage(issue)
+	if in.Sent != nil && in.Sent.Seen(issue.ID) {
+		return ErrAlreadySent
+	}
+	// A notification that reached some channels is recorded so a restart
+	// doesn't send it to those channels again
+	err := in.notifyNewIssue(issue)
+	if err == nil || IsPartial(err) {
+		in.recordSent(issue)
+	}
+	return err
+}
+
+// recordSent adds issue to Sent. A failure is only logged since the
+// notification itself went out.
+func (in *IssueNotifier) recordSent(issue issue.Issue) {
+	if in.Sent == nil {
+		return
+	}
+	if err := in.Sent.Record(issue.ID); err != nil {
+		slog.Error("Error recording notified issue", "event", "new_issue", "issue_number", issue.Number, "error", err)
+	}
+}
+
+// notifyNewIssue formats and sends the new issue notification
+func (in *IssueNotifier) notifyNewIssue(issue issue.Issue) error {
+	if in.Format == FormatCompact {
+		if issue.PullRequest != nil {
+			return in.notifier.Notify(fmt.Sprintf("PR #%d", issue.Number), "", issue.HTMLURL)
+		}
+		return in.notifier.Notify(fmt.Sprintf("#%d", issue.Number), "", issue.HTMLURL)
+	}
+
+	title := in.taggedIssue(issue, "New GitHub Issue")
+	if issue.PullRequest != nil {
+		title = in.taggedIssue(issue, "New GitHub Pull Request")
+	}
+	message := formatIssueMessage(in.rendered(issue))
+	if in.MessageTemplate != nil {
+		message = formatIssueTemplate(in.MessageTemplate, in.rendered(issue))
+	}
 	return in.notifier.Notify(title, message, issue.HTMLURL)
 }
 
+// NotifyStateChange sends a notification for an issue that moved between states
+func (in *IssueNotifier) NotifyStateChange(issue issue.Issue, from, to string) error {
+	title := in.taggedIssue(issue, fmt.Sprintf("Issue #%d %s", issue.Number, stateChangeVerb(from, to)))
+	return in.notifier.Notify(title, issue.Title, issue.HTMLURL)
+}
+
+// NotifyBatch sends one summary notification for several new issues, e.g.
+// "5 new issues in owner/repo". repo may be empty when they span repositories.
+// Issues already in Sent are left out.
+func (in *IssueNotifier) NotifyBatch(issues []issue.Issue, repo, url string) error {
+	if in.Sent != nil {
+		unsent := make([]issue.Issue, 0, len(issues))
+		for _, i := range issues {
+			if !in.Sent.Seen(i.ID) {
+				unsent = append(unsent, i)
+			}
+		}
+		if len(unsent) == 0 {
+			return nil
+		}
+		issues = unsent
+	}
+
+	title := fmt.Sprintf("%d new issues", len(issues))
+	if repo != "" {
+		title += " in " + repo
+	}
+
+	numbers := make([]string, 0, len(issues))
+	for _, i := range issues {
+		numbers = append(numbers, fmt.Sprintf("#%d", i.Number))
+	}
+	message := strings.Join(numbers, ", ")
+	if len(message) > config.MaxNotificationLength {
+		message = message[:config.MaxNotificationLength-3] + "..."
+	}
+	err := in.notifier.Notify(in.tagged(title), message, url)
+	if err == nil || IsPartial(err) {
+		for _, i := range issues {
+			in.recordSent(i)
+		}
+	}
+	return err
+}
+
+// NotifyStarMilestone sends a notification for a repository crossing a star milestone
+func (in *IssueNotifier) NotifyStarMilestone(repo string, milestone, stars int, url string) error {
+	title := in.tagged(fmt.Sprintf("%s reached %d stars", repo, milestone))
+	message := fmt.Sprintf("Now at %d stargazers", stars)
+	return in.notifier.Notify(title, message, url)
+}
+
+// NotifyReminder re-sends a notification for an issue that is still open
+func (in *IssueNotifier) NotifyReminder(issue issue.Issue, openFor time.Duration) error {
+	title := in.taggedIssue(issue, fmt.Sprintf("Reminder: #%d still open after %s", issue.Number, FormatAge(openFor)))
+	return in.notifier.Notify(title, in.rendered(issue).Title, issue.HTMLURL)
+}
+
+// NotifyMilestoneDeadline sends a reminder for an open issue whose milestone is due soon
+func (in *IssueNotifier) NotifyMilestoneDeadline(issue issue.Issue, milestone string, dueIn time.Duration) error {
+	title := in.taggedIssue(issue, fmt.Sprintf("#%d: %s due in %s", issue.Number, milestone, FormatAge(dueIn)))
+	return in.notifier.Notify(title, in.rendered(issue).Title, issue.HTMLURL)
+}
+
+// NotifyComment sends a notification for a new comment, showing its author and the start of its text
+func (in *IssueNotifier) NotifyComment(comment repository.Comment) error {
+	title := in.tagged(fmt.Sprintf("New comment on #%d", comment.IssueNumber()))
+
+	body := strings.Join(strings.Fields(comment.Body), " ")
+	if in.RenderMarkdown {
+		body = markdown.ToPlainText(body)
+	}
+	if runes := []rune(body); len(runes) > config.MaxNotificationLength {
+		body = string(runes[:config.MaxNotificationLength-3]) + "..."
+	}
+	return in.notifier.Notify(title, fmt.Sprintf("%s: %s", comment.User.Login, body), comment.HTMLURL)
+}
+
+// FormatAge renders a duration in the largest whole unit, e.g. "2d", "5h" or "30m"
+func FormatAge(d time.Duration) string {
+	switch {
+	case d >= 24*time.Hour:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+}
+
+// NotifyStartup sends a summary once the service has completed its first poll
+func (in *IssueNotifier) NotifyStartup(version string, sources, baselined, failed int, url string) error {
+	title := fmt.Sprintf("gitnotifier %s started", version)
+	message := fmt.Sprintf("Watching %d %s, %d issues baselined", sources, plural(sources, "source", "sources"), baselined)
+	if failed > 0 {
+		message += fmt.Sprintf(" (%d failed their first check)", failed)
+	}
+	return in.notifier.Notify(title, message, url)
+}
+
+func plural(n int, singular, plural string) string {
+	if n == 1 {
+		return singular
+	}
+	return plural
+}
+
+// NotifyProjectMatch sends a notification for a project item whose field now matches a watched value
+func (in *IssueNotifier) NotifyProjectMatch(item repository.ProjectItem, field, value string) error {
+	title := in.tagged(fmt.Sprintf("Project item matches %s = %s", field, value))
+	message := item.Title
+	if item.Number != 0 {
+		message = fmt.Sprintf("#%d: %s", item.Number, item.Title)
+	}
+	if in.RenderMarkdown {
+		message = markdown.ToPlainText(message)
+	}
+	return in.notifier.Notify(title, message, item.URL)
+}
+
+// NotifyWorkflowRun sends a notification for a completed workflow run
+func (in *IssueNotifier) NotifyWorkflowRun(run repository.WorkflowRun) error {
+	title := in.tagged(fmt.Sprintf("Workflow %s: %s on %s", run.Name, run.Conclusion, run.HeadBranch))
+	message := fmt.Sprintf("Run #%d: %s", run.RunNumber, run.DisplayTitle)
+	return in.notifier.Notify(title, message, run.HTMLURL)
+}
+
+// NotifyAdvisory sends a notification for a new security advisory or Dependabot alert
+func (in *IssueNotifier) NotifyAdvisory(advisory repository.Advisory) error {
+	kind := "Security advisory"
+	if advisory.Source == "dependabot" {
+		kind = "Dependabot alert"
+	}
+	title := in.tagged(fmt.Sprintf("%s (%s severity)", kind, advisory.Severity))
+	return in.notifier.Notify(title, advisory.Summary, advisory.HTMLURL)
+}
+
+// rendered returns a copy of the issue with its text prepared for display
+func (in *IssueNotifier) rendered(issue issue.Issue) issue.Issue {
+	if in.RenderMarkdown {
+		issue.Title = markdown.ToPlainText(issue.Title)
+	}
+	return issue
+}
+
+func (in *IssueNotifier) tagged(title string) string {
+	if in.Tag == "" {
+		return title
+	}
+	return fmt.Sprintf("[%s] %s", in.Tag, title)
+}
+
+// taggedIssue is like tagged but uses the issue's repository as the tag when TagWithRepo is set
+func (in *IssueNotifier) taggedIssue(issue issue.Issue, title string) string {
+	if name := issue.RepoFullName(); in.TagWithRepo && name != "" {
+		return fmt.Sprintf("[%s] %s", name, title)
+	}
+	return in.tagged(title)
+}
+
+// NotifyVanished sends a notification for an open issue that disappeared from
+// the repository. newURL is its new location if it was transferred.
+func (in *IssueNotifier) NotifyVanished(issue issue.Issue, newURL string) error {
+	if newURL != "" {
+		title := in.taggedIssue(issue, fmt.Sprintf("Issue #%d was transferred", issue.Number))
+		return in.notifier.Notify(title, in.rendered(issue).Title, newURL)
+	}
+	title := in.taggedIssue(issue, fmt.Sprintf("Issue #%d was transferred or deleted", issue.Number))
+	return in.notifier.Notify(title, in.rendered(issue).Title, issue.HTMLURL)
+}
+
+func stateChangeVerb(from, to string) string {
+	switch {
+	case to == "closed":
+		return "closed"
+	case from == "closed" && to == "open":
+		return "reopened"
+	default:
+		return "is now " + to
+	}
+}
+
 func formatIssueMessage(issue issue.Issue) string {
-	return fmt.Sprintf("#%d: %s", issue.Number, issue.Title)
+	message := fmt.Sprintf("#%d: %s", issue.Number, issue.Title)
+	if issue.User.Login != "" {
+		message = fmt.Sprintf("#%d by %s: %s", issue.Number, issue.User.Login, issue.Title)
+	}
+	if len(issue.LinkedPullRequests) == 0 {
+		return message
+	}
+
+	prs := make([]string, 0, len(issue.LinkedPullRequests))
+	for _, pr := range issue.LinkedPullRequests {
+		prs = append(prs, fmt.Sprintf("PR #%d %s", pr.Number, pr.State))
+	}
+	return fmt.Sprintf("%s (%s)", message, strings.Join(prs, ", "))
 }
 
-// NewPlatformNotifier creates the appropriate notifier for the current platform
-func NewPlatformNotifier() (Notifier, error) {
+// NewPlatformNotifier creates the appropriate notifier for the current platform.
+// icons, which may be nil, are shown on Linux and Windows. sound names the
+// macOS system sound; empty plays the default and platform.SoundNone silences
+// notifications everywhere.
+func NewPlatformNotifier(icons platform.Icons, sound string) (Notifier, error) {
+	silent := sound == platform.SoundNone
 	switch runtime.GOOS {
 	case "darwin":
-		return platform.NewMacOSNotifier(), nil
+		return platform.NewMacOSNotifier(sound), nil
 	case "windows":
-		return platform.NewWindowsNotifier(), nil
+		return platform.NewWindowsNotifier(icons, silent), nil
 	case "linux":
-		return platform.NewLinuxNotifier(), nil
+		return platform.NewLinuxNotifier(icons, silent), nil
 	default:
 		return nil, fmt.Errorf("unsupported operating system: %s", runtime.GOOS)
 	}
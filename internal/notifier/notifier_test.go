@@ -0,0 +1,70 @@
+package notifier
+
+import (
+	"gitnotifier/internal/issue"
+	"sync"
+	"testing"
+)
+
+// sentNotification is one call recorded by recordingNotifier
+type sentNotification struct {
+	Title, Message, URL string
+}
+
+// recordingNotifier is a fake Notifier that records what was sent and returns err
+type recordingNotifier struct {
+	mu   sync.Mutex
+	sent []sentNotification
+	err  error
+}
+
+func (r *recordingNotifier) Notify(title, message, url string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sent = append(r.sent, sentNotification{Title: title, Message: message, URL: url})
+	return r.err
+}
+
+// last returns the most recent notification, failing the test if there is none
+func (r *recordingNotifier) last(t *testing.T) sentNotification {
+	t.Helper()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.sent) == 0 {
+		t.Fatal("nothing was notified")
+	}
+	return r.sent[len(r.sent)-1]
+}
+
+var markdownIssue = issue.Issue{
+	ID:      142,
+	Number:  42,
+	Title:   "Crash in **`Parse`**, see [logs](https://example.com/logs)",
+	HTMLURL: "https://github.com/owner/repo/issues/42",
+	User:    issue.User{Login: "alice"},
+}
+
+func TestNotifyNewIssueRenderMarkdown(t *testing.T) {
+	tests := []struct {
+		name   string
+		render bool
+		want   string
+	}{
+		{"off", false, "#42 by alice: Crash in **`Parse`**, see [logs](https://example.com/logs)"},
+		{"on", true, "#42 by alice: Crash in Parse, see logs (https://example.com/logs)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := &recordingNotifier{}
+			in := NewIssueNotifier(rec)
+			in.RenderMarkdown = tt.render
+
+			if err := in.NotifyNewIssue(markdownIssue); err != nil {
+				t.Fatalf("NotifyNewIssue: %v", err)
+			}
+			if got := rec.last(t).Message; got != tt.want {
+				t.Errorf("message = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
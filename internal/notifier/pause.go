@@ -0,0 +1,105 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// ErrPaused is returned by PausableNotifier for a notification dropped while
+// paused, so callers can advance their state without recording it as sent
+var ErrPaused = errors.New("notifications are paused")
+
+// PausableNotifier drops notifications while paused, either manually or while
+// a pause file exists, so services keep advancing their state quietly
+type PausableNotifier struct {
+	notifier Notifier
+	file     string
+	mu       sync.Mutex
+	manual   bool
+	paused   bool // Last reported state, used to log transitions
+}
+
+// NewPausableNotifier wraps n. file is optional; when set, notifications are
+// paused for as long as it exists.
+func NewPausableNotifier(n Notifier, file string) *PausableNotifier {
+	return &PausableNotifier{
+		notifier: n,
+		file:     file,
+	}
+}
+
+func (p *PausableNotifier) Notify(title, message, url string) error {
+	if p.isPaused() {
+		slog.Info("Notifications paused, not sending", "event", "pause", "title", title)
+		return ErrPaused
+	}
+	return p.notifier.Notify(title, message, url)
+}
+
+// Pause suspends delivery until Resume is called
+func (p *PausableNotifier) Pause() {
+	p.mu.Lock()
+	p.manual = true
+	p.mu.Unlock()
+	p.isPaused()
+}
+
+// Resume lifts a Pause. Delivery stays suspended while the pause file exists.
+func (p *PausableNotifier) Resume() {
+	p.mu.Lock()
+	p.manual = false
+	p.mu.Unlock()
+	p.isPaused()
+}
+
+// Paused reports whether delivery is currently suspended
+func (p *PausableNotifier) Paused() bool {
+	return p.isPaused()
+}
+
+// Run checks the pause file every interval until ctx is cancelled, so file
+// changes are logged when they happen rather than at the next notification
+func (p *PausableNotifier) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			p.isPaused()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// isPaused reports whether delivery is suspended, logging any change since the last check
+func (p *PausableNotifier) isPaused() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	fromFile := false
+	if p.file != "" {
+		if _, err := os.Stat(p.file); err == nil {
+			fromFile = true
+		}
+	}
+
+	paused := p.manual || fromFile
+	if paused != p.paused {
+		switch {
+		case !paused:
+			slog.Info("Notifications resumed", "event", "pause")
+		case p.manual:
+			slog.Info("Notifications paused by signal", "event", "pause")
+		default:
+			slog.Info("Notifications paused while the pause file exists", "event", "pause", "file", p.file)
+		}
+		p.paused = paused
+	}
+	return paused
+}
@@ -0,0 +1,46 @@
+package notifier
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPausableNotifier(t *testing.T) {
+	rec := &recordingNotifier{}
+	file := filepath.Join(t.TempDir(), "pause")
+	p := NewPausableNotifier(rec, file)
+
+	notify := func() error {
+		return p.Notify("New GitHub Issue", "#1: Crash", "https://github.com/owner/repo/issues/1")
+	}
+	steps := []struct {
+		name    string
+		change  func()
+		wantErr error
+	}{
+		{"running", func() {}, nil},
+		{"paused by signal", p.Pause, ErrPaused},
+		{"resumed", p.Resume, nil},
+		{"pause file created", func() { os.WriteFile(file, nil, 0o644) }, ErrPaused},
+		{"resume keeps the file's pause", p.Resume, ErrPaused},
+		{"pause file removed", func() { os.Remove(file) }, nil},
+	}
+	sent := 0
+	for _, step := range steps {
+		step.change()
+		if err := notify(); !errors.Is(err, step.wantErr) {
+			t.Fatalf("%s: Notify = %v, want %v", step.name, err, step.wantErr)
+		}
+		if step.wantErr == nil {
+			sent++
+		}
+		if got := len(rec.sent); got != sent {
+			t.Errorf("%s: %d notifications delivered, want %d", step.name, got, sent)
+		}
+		if p.Paused() != (step.wantErr != nil) {
+			t.Errorf("%s: Paused() = %v", step.name, p.Paused())
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package platform
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	ansiReset = "\033[0m"
+	ansiDim   = "\033[2m"
+	ansiBold  = "\033[1m"
+	ansiBlue  = "\033[34m"
+)
+
+// ConsoleNotifier prints notifications as readable lines, for watching new
+// issues from a terminal
+type ConsoleNotifier struct {
+	out   io.Writer
+	color bool
+	mu    sync.Mutex
+}
+
+// NewConsoleNotifier prints to stdout, with colors when it is a terminal and
+// NO_COLOR is not set
+func NewConsoleNotifier() *ConsoleNotifier {
+	color := false
+	if info, err := os.Stdout.Stat(); err == nil && info.Mode()&os.ModeCharDevice != 0 {
+		color = os.Getenv("NO_COLOR") == ""
+	}
+	return &ConsoleNotifier{
+		out:   os.Stdout,
+		color: color,
+	}
+}
+
+func (n *ConsoleNotifier) Notify(title, message, url string) error {
+	timestamp := time.Now().Format("2006-01-02 15:04:05")
+	text := title
+	if message != "" {
+		text = fmt.Sprintf("%s: %s", title, message)
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	var err error
+	if n.color {
+		_, err = fmt.Fprintf(n.out, "%s%s%s  %s%s%s  %s%s%s\n",
+			ansiDim, timestamp, ansiReset, ansiBold, text, ansiReset, ansiBlue, url, ansiReset)
+	} else {
+		_, err = fmt.Fprintf(n.out, "%s  %s  %s\n", timestamp, text, url)
+	}
+	if err != nil {
+		return fmt.Errorf("error writing to console: %v", err)
+	}
+	return nil
+}
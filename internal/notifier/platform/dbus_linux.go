@@ -0,0 +1,65 @@
+//go:build linux
+
+package platform
+
+import (
+	"fmt"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// DBusNotifier sends desktop notifications over D-Bus, which supports
+// replacing an earlier notification in place by its ID
+type DBusNotifier struct {
+	conn   *dbus.Conn
+	icons  Icons
+	silent bool
+}
+
+// NewDBusNotifier creates a DBusNotifier that, like LinuxNotifier, shows the
+// icon from icons for each notification's repository owner and, when silent,
+// asks the notification server not to play a sound
+func NewDBusNotifier(icons Icons, silent bool) (*DBusNotifier, error) {
+	conn, err := dbus.SessionBus()
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to the D-Bus session bus: %v", err)
+	}
+	return &DBusNotifier{conn: conn, icons: icons, silent: silent}, nil
+}
+
+func (n *DBusNotifier) object() dbus.BusObject {
+	return n.conn.Object("org.freedesktop.Notifications", "/org/freedesktop/Notifications")
+}
+
+// Replace shows a notification replacing the one with replacesID (0 for a new
+// one) and returns the ID of the notification shown
+func (n *DBusNotifier) Replace(replacesID uint32, title, message, url string) (uint32, error) {
+	hints := map[string]dbus.Variant{}
+	if n.silent {
+		hints["suppress-sound"] = dbus.MakeVariant(true)
+	}
+	call := n.object().Call("org.freedesktop.Notifications.Notify", 0,
+		"gitnotifier",
+		replacesID,
+		n.icons.For(url),
+		title,
+		fmt.Sprintf("%s\n%s", message, url),
+		[]string{},
+		hints,
+		int32(-1),
+	)
+
+	var id uint32
+	if err := call.Store(&id); err != nil {
+		return 0, fmt.Errorf("error sending D-Bus notification: %v", err)
+	}
+	return id, nil
+}
+
+// Close removes the notification with id
+func (n *DBusNotifier) Close(id uint32) error {
+	if err := n.object().Call("org.freedesktop.Notifications.CloseNotification", 0, id).Err; err != nil {
+		return fmt.Errorf("error closing D-Bus notification: %v", err)
+	}
+	return nil
+}
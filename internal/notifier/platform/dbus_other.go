@@ -0,0 +1,23 @@
+//go:build !linux
+
+package platform
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// DBusNotifier is only available on Linux
+type DBusNotifier struct{}
+
+func NewDBusNotifier(icons Icons, silent bool) (*DBusNotifier, error) {
+	return nil, fmt.Errorf("replacing notifications is not supported on %s", runtime.GOOS)
+}
+
+func (n *DBusNotifier) Replace(replacesID uint32, title, message, url string) (uint32, error) {
+	return 0, fmt.Errorf("replacing notifications is not supported on %s", runtime.GOOS)
+}
+
+func (n *DBusNotifier) Close(id uint32) error {
+	return fmt.Errorf("replacing notifications is not supported on %s", runtime.GOOS)
+}
@@ -0,0 +1,115 @@
+package platform
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"gitnotifier/config"
+	"gitnotifier/internal/ctxutil"
+	"net/http"
+	"time"
+)
+
+// DiscordNotifier posts notifications as embeds to a Discord webhook
+type DiscordNotifier struct {
+	ctx        context.Context // Ends rate limit waits, e.g. on shutdown
+	client     *http.Client
+	webhookURL string
+	maxRetries int
+}
+
+// NewDiscordNotifier creates a notifier for webhookURL. Rate-limited posts are
+// retried up to maxRetries times after the delay Discord asks for, capped at
+// config.MaxRetryAfter, unless ctx is done first.
+func NewDiscordNotifier(ctx context.Context, client *http.Client, webhookURL string, maxRetries int) *DiscordNotifier {
+	return &DiscordNotifier{
+		ctx:        ctx,
+		client:     client,
+		webhookURL: webhookURL,
+		maxRetries: maxRetries,
+	}
+}
+
+type discordEmbed struct {
+	Title  string         `json:"title"`
+	URL    string         `json:"url,omitempty"`
+	Author *discordAuthor `json:"author,omitempty"`
+	Footer *discordFooter `json:"footer,omitempty"`
+}
+
+type discordAuthor struct {
+	Name string `json:"name"`
+}
+
+type discordFooter struct {
+	Text string `json:"text"`
+}
+
+func (n *DiscordNotifier) Notify(title, message, url string) error {
+	// The message holds the issue title for new issues, so it becomes the
+	// linked embed title with the notification kind shown above it
+	embed := discordEmbed{Title: title, URL: url}
+	if message != "" {
+		embed.Title = message
+		embed.Author = &discordAuthor{Name: title}
+	}
+	if repo, number, ok := issueFromURL(url); ok {
+		embed.Footer = &discordFooter{Text: fmt.Sprintf("%s #%s", repo, number)}
+	}
+
+	body, err := json.Marshal(map[string]any{"embeds": []discordEmbed{embed}})
+	if err != nil {
+		return fmt.Errorf("error encoding Discord message: %v", err)
+	}
+
+	for attempt := 0; ; attempt++ {
+		retryAfter, err := n.post(body)
+		if err == nil {
+			return nil
+		}
+		if retryAfter == 0 || attempt == n.maxRetries {
+			return err
+		}
+		if err := ctxutil.Sleep(n.ctx, min(retryAfter, config.MaxRetryAfter)); err != nil {
+			return err
+		}
+	}
+}
+
+// post performs one attempt and returns how long to wait when Discord rate limited it
+func (n *DiscordNotifier) post(body []byte) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), n.client.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("error creating Discord request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("error posting to Discord: %v", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusNoContent:
+		return 0, nil
+	case resp.StatusCode == http.StatusTooManyRequests:
+		var limited struct {
+			RetryAfter float64 `json:"retry_after"` // Seconds
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&limited)
+		retryAfter := time.Duration(limited.RetryAfter * float64(time.Second))
+		if retryAfter <= 0 {
+			retryAfter = time.Second
+		}
+		return retryAfter, fmt.Errorf("Discord rate limited the message")
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusUnauthorized:
+		return 0, fmt.Errorf("Discord webhook not found. Please check DISCORD_WEBHOOK_URL")
+	default:
+		return 0, fmt.Errorf("Discord webhook returned status code: %d", resp.StatusCode)
+	}
+}
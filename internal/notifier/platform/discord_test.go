@@ -0,0 +1,56 @@
+package platform
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDiscordNotifierRateLimitWaitEndsWithContext(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		io.WriteString(w, `{"message": "You are being rate limited.", "retry_after": 3600}`)
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	client := srv.Client()
+	client.Timeout = time.Second
+
+	start := time.Now()
+	err := NewDiscordNotifier(ctx, client, srv.URL, 3).Notify("New GitHub Issue", "#1: Crash", "https://github.com/owner/repo/issues/1")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Notify = %v, want context.DeadlineExceeded", err)
+	}
+	if waited := time.Since(start); waited > time.Second {
+		t.Errorf("Notify waited %v for an hour-long rate limit after ctx was done", waited)
+	}
+}
+
+func TestDiscordNotifierRetriesAfterRateLimit(t *testing.T) {
+	var posts atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if posts.Add(1) == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			io.WriteString(w, `{"retry_after": 0.01}`)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	client := srv.Client()
+	client.Timeout = time.Second
+	if err := NewDiscordNotifier(context.Background(), client, srv.URL, 3).Notify("title", "message", "https://github.com"); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	if got := posts.Load(); got != 2 {
+		t.Errorf("posted %d times, want 2", got)
+	}
+}
@@ -0,0 +1,171 @@
+package platform
+
+import (
+	"bytes"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"html"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EmailNotifier sends notifications as email through an SMTP server. Port 465
+// uses implicit TLS, other ports upgrade with STARTTLS when the server offers it.
+type EmailNotifier struct {
+	host     string
+	port     int
+	username string
+	password string
+	from     string
+	to       []string
+	timeout  time.Duration
+}
+
+// NewEmailNotifier creates an SMTP notifier. username may be empty for servers without authentication.
+func NewEmailNotifier(host string, port int, username, password, from string, to []string, timeout time.Duration) (*EmailNotifier, error) {
+	if host == "" || from == "" || len(to) == 0 {
+		return nil, fmt.Errorf("SMTP host, sender and at least one recipient are required")
+	}
+	if port <= 0 {
+		port = 587
+	}
+	return &EmailNotifier{
+		host:     host,
+		port:     port,
+		username: username,
+		password: password,
+		from:     from,
+		to:       to,
+		timeout:  timeout,
+	}, nil
+}
+
+func (n *EmailNotifier) Notify(title, message, url string) error {
+	msg, err := n.buildMessage(title, message, url)
+	if err != nil {
+		return err
+	}
+
+	client, err := n.connect()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if n.username != "" {
+		if err := client.Auth(smtp.PlainAuth("", n.username, n.password, n.host)); err != nil {
+			return smtpAuthError(err)
+		}
+	}
+
+	if err := client.Mail(n.from); err != nil {
+		return fmt.Errorf("SMTP server rejected sender %s: %v", n.from, err)
+	}
+	for _, rcpt := range n.to {
+		if err := client.Rcpt(rcpt); err != nil {
+			return fmt.Errorf("SMTP server rejected recipient %s: %v", rcpt, err)
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("error sending email: %v", err)
+	}
+	if _, err := w.Write(msg); err != nil {
+		return fmt.Errorf("error sending email: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("error sending email: %v", err)
+	}
+	return client.Quit()
+}
+
+// connect dials the server, using implicit TLS on port 465 and STARTTLS elsewhere when offered
+func (n *EmailNotifier) connect() (*smtp.Client, error) {
+	addr := net.JoinHostPort(n.host, strconv.Itoa(n.port))
+	tlsConfig := &tls.Config{ServerName: n.host}
+
+	var conn net.Conn
+	var err error
+	if n.port == 465 {
+		conn, err = tls.DialWithDialer(&net.Dialer{Timeout: n.timeout}, "tcp", addr, tlsConfig)
+	} else {
+		conn, err = net.DialTimeout("tcp", addr, n.timeout)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to SMTP server %s: %v", addr, err)
+	}
+	// Bound the whole exchange so a stalled server can't hang the poll loop
+	conn.SetDeadline(time.Now().Add(n.timeout))
+
+	client, err := smtp.NewClient(conn, n.host)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("error starting SMTP session with %s: %v", addr, err)
+	}
+
+	if n.port != 465 {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(tlsConfig); err != nil {
+				client.Close()
+				return nil, fmt.Errorf("error upgrading SMTP connection to TLS: %v", err)
+			}
+		} else if n.port == 587 {
+			client.Close()
+			return nil, fmt.Errorf("SMTP server %s does not support STARTTLS on port 587", addr)
+		}
+	}
+	return client, nil
+}
+
+// buildMessage renders a multipart/alternative email with plain text and HTML parts
+func (n *EmailNotifier) buildMessage(title, message, url string) ([]byte, error) {
+	var body bytes.Buffer
+	parts := multipart.NewWriter(&body)
+
+	text, err := parts.CreatePart(textproto.MIMEHeader{"Content-Type": {"text/plain; charset=utf-8"}})
+	if err != nil {
+		return nil, fmt.Errorf("error building email: %v", err)
+	}
+	fmt.Fprintf(text, "%s\r\n\r\n%s\r\n", message, url)
+
+	htmlPart, err := parts.CreatePart(textproto.MIMEHeader{"Content-Type": {"text/html; charset=utf-8"}})
+	if err != nil {
+		return nil, fmt.Errorf("error building email: %v", err)
+	}
+	fmt.Fprintf(htmlPart, "<p>%s</p>\r\n<p><a href=\"%s\">%s</a></p>\r\n",
+		html.EscapeString(message), html.EscapeString(url), html.EscapeString(url))
+
+	if err := parts.Close(); err != nil {
+		return nil, fmt.Errorf("error building email: %v", err)
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", n.from)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(n.to, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", title))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&msg, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&msg, "Content-Type: multipart/alternative; boundary=%s\r\n\r\n", parts.Boundary())
+	msg.Write(body.Bytes())
+	return msg.Bytes(), nil
+}
+
+// smtpAuthError turns authentication failures into actionable messages
+func smtpAuthError(err error) error {
+	var protoErr *textproto.Error
+	if errors.As(err, &protoErr) && (protoErr.Code == 535 || protoErr.Code == 534) {
+		return fmt.Errorf("SMTP server rejected the credentials. Please check SMTP_USER and SMTP_PASS")
+	}
+	if strings.Contains(err.Error(), "unencrypted connection") {
+		return fmt.Errorf("SMTP server does not support TLS, refusing to send credentials in plaintext")
+	}
+	return fmt.Errorf("SMTP authentication failed: %v", err)
+}
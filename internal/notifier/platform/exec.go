@@ -0,0 +1,61 @@
+package platform
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"time"
+)
+
+// ExecNotifier delivers notifications by running a user supplied command.
+// The title, message and URL are passed both as trailing arguments and as
+// the GN_TITLE, GN_MESSAGE and GN_URL environment variables.
+type ExecNotifier struct {
+	command string
+	args    []string
+	timeout time.Duration
+}
+
+func NewExecNotifier(command string, args []string, timeout time.Duration) *ExecNotifier {
+	return &ExecNotifier{
+		command: command,
+		args:    args,
+		timeout: timeout,
+	}
+}
+
+func (n *ExecNotifier) Notify(title, message, url string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), n.timeout)
+	defer cancel()
+
+	args := append(append([]string{}, n.args...), title, message, url)
+	cmd := exec.CommandContext(ctx, n.command, args...)
+	cmd.Env = append(os.Environ(),
+		"GN_TITLE="+title,
+		"GN_MESSAGE="+message,
+		"GN_URL="+url,
+	)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	if out := bytes.TrimSpace(stdout.Bytes()); len(out) > 0 {
+		slog.Info("Notification command output", "event", "notification", "command", n.command, "stdout", string(out))
+	}
+	if out := bytes.TrimSpace(stderr.Bytes()); len(out) > 0 {
+		slog.Warn("Notification command output", "event", "notification", "command", n.command, "stderr", string(out))
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("%s timed out after %v", n.command, n.timeout)
+	}
+	if err != nil {
+		return fmt.Errorf("%s failed: %v", n.command, err)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package platform
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Icons maps repository owners to local image files shown as the icon of
+// their desktop notifications
+type Icons map[string]string
+
+// For returns the icon for the owner of the repository that link points to,
+// e.g. https://github.com/owner/repo/issues/1, or "" if there is none
+func (i Icons) For(link string) string {
+	u, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+	owner, _, _ := strings.Cut(strings.TrimPrefix(u.Path, "/"), "/")
+	return i[strings.ToLower(owner)]
+}
+
+// DownloadIcon saves the image at imageURL, such as an owner's avatar, to the
+// temp directory and returns its path. The file is reused across restarts.
+func DownloadIcon(ctx context.Context, client *http.Client, owner, imageURL string) (string, error) {
+	u, err := url.Parse(imageURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid icon URL %q: %v", imageURL, err)
+	}
+	// Desktop icons are small, so ask for a small avatar
+	q := u.Query()
+	q.Set("s", "128")
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return "", fmt.Errorf("error creating icon request: %v", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error downloading icon: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("icon download returned status code: %d", resp.StatusCode)
+	}
+
+	path := filepath.Join(os.TempDir(), fmt.Sprintf("gitnotifier-avatar-%s.png", strings.ToLower(owner)))
+	f, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("error saving icon: %v", err)
+	}
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		f.Close()
+		return "", fmt.Errorf("error saving icon: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		return "", fmt.Errorf("error saving icon: %v", err)
+	}
+	return path, nil
+}
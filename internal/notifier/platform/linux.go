@@ -9,17 +9,30 @@ import (
 
 // LinuxNotifier implements desktop notifications for Linux
 type LinuxNotifier struct {
+	icons  Icons
+	silent bool
 }
 
-func NewLinuxNotifier() *LinuxNotifier {
-	return &LinuxNotifier{}
+// NewLinuxNotifier creates a LinuxNotifier showing the icon from icons for
+// each notification's repository owner; icons may be nil. When silent, the
+// notification server is asked not to play a sound.
+func NewLinuxNotifier(icons Icons, silent bool) *LinuxNotifier {
+	return &LinuxNotifier{icons: icons, silent: silent}
 }
 
 func (n *LinuxNotifier) Notify(title, message, url string) error {
-	cmd := exec.Command("notify-send", title, fmt.Sprintf("%s\n%s", message, url))
+	icon := n.icons.For(url)
+	args := []string{title, fmt.Sprintf("%s\n%s", message, url)}
+	if icon != "" {
+		args = append([]string{"-i", icon}, args...)
+	}
+	if n.silent {
+		args = append([]string{"-h", "boolean:suppress-sound:true"}, args...)
+	}
+	cmd := exec.Command("notify-send", args...)
 	if err := cmd.Run(); err != nil {
 		// Fall back to beeep if native notifications fail
-		return beeep.Notify(title, fmt.Sprintf("%s\n\nClick to open: %s", message, url), "")
+		return beeep.Notify(title, fmt.Sprintf("%s\n\nClick to open: %s", message, url), icon)
 	}
 	return nil
 }
@@ -3,27 +3,69 @@ package platform
 import (
 	"fmt"
 	"os/exec"
+	"strings"
 )
 
-// MacOSNotifier implements desktop notifications for macOS
-type MacOSNotifier struct{}
+// SoundNone selects silent desktop notifications on every platform
+const SoundNone = "none"
 
-func NewMacOSNotifier() *MacOSNotifier {
-	return &MacOSNotifier{}
+// MacOSNotifier implements desktop notifications for macOS. It uses
+// terminal-notifier, which opens the URL when the notification is clicked,
+// and falls back to osascript when terminal-notifier is not installed.
+type MacOSNotifier struct {
+	terminalNotifier string // Path to terminal-notifier, empty when not installed
+	sound            string
+}
+
+// NewMacOSNotifier creates a MacOSNotifier playing the named system sound,
+// such as "Glass". An empty sound plays the default one and SoundNone none.
+func NewMacOSNotifier(sound string) *MacOSNotifier {
+	path, _ := exec.LookPath("terminal-notifier")
+	if sound == "" {
+		sound = "default"
+	}
+	return &MacOSNotifier{terminalNotifier: path, sound: sound}
 }
 
 func (n *MacOSNotifier) Notify(title, message, url string) error {
-	cmd := exec.Command("terminal-notifier",
-		"-title", title,
-		"-message", message,
-		"-open", url,
-		"-sound", "default")
+	if n.terminalNotifier == "" {
+		return n.notifyOSAScript(title, message, url)
+	}
 
+	// terminal-notifier refuses an empty message
+	if message == "" {
+		message = url
+	}
+
+	args := []string{"-title", title, "-message", message, "-open", url}
+	if n.sound != SoundNone {
+		args = append(args, "-sound", n.sound)
+	}
+	cmd := exec.Command(n.terminalNotifier, args...)
 	if err := cmd.Run(); err != nil {
-		if _, ok := err.(*exec.ExitError); ok {
-			return fmt.Errorf("terminal-notifier not installed. Please install with: brew install terminal-notifier")
-		}
-		return err
+		return fmt.Errorf("error running terminal-notifier: %v", err)
 	}
 	return nil
 }
+
+// notifyOSAScript shows a notification with AppleScript. It can't open the
+// URL on click, so the URL is included in the text instead.
+func (n *MacOSNotifier) notifyOSAScript(title, message, url string) error {
+	script := fmt.Sprintf("display notification %s with title %s",
+		appleScriptString(strings.TrimSpace(message+"\n"+url)), appleScriptString(title))
+	// AppleScript has no name for the default sound, so only a chosen one is played
+	if n.sound != SoundNone && n.sound != "default" {
+		script += " sound name " + appleScriptString(n.sound)
+	}
+	if err := exec.Command("osascript", "-e", script).Run(); err != nil {
+		return fmt.Errorf("error running osascript: %v. For clickable notifications install terminal-notifier with: brew install terminal-notifier", err)
+	}
+	return nil
+}
+
+// appleScriptString quotes s as an AppleScript string literal
+func appleScriptString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
@@ -0,0 +1,137 @@
+package platform
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"gitnotifier/config"
+	"gitnotifier/internal/ctxutil"
+	"html"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// MatrixNotifier sends notifications as messages to a Matrix room using the
+// client-server API
+type MatrixNotifier struct {
+	ctx         context.Context // Ends rate limit waits, e.g. on shutdown
+	client      *http.Client
+	homeserver  string
+	accessToken string
+	roomID      string
+	maxRetries  int
+	txnPrefix   string
+	txnCounter  atomic.Int64
+}
+
+// NewMatrixNotifier creates a notifier posting to roomID on homeserver (e.g.
+// "https://matrix.org"). Rate-limited sends are retried up to maxRetries times,
+// waiting at most config.MaxRetryAfter each time or until ctx is done.
+func NewMatrixNotifier(ctx context.Context, client *http.Client, homeserver, accessToken, roomID string, maxRetries int) (*MatrixNotifier, error) {
+	if accessToken == "" || roomID == "" {
+		return nil, fmt.Errorf("a Matrix access token and room ID are required")
+	}
+	u, err := url.Parse(homeserver)
+	if err != nil || u.Host == "" || (u.Scheme != "https" && u.Scheme != "http") {
+		return nil, fmt.Errorf("invalid Matrix homeserver URL %q", homeserver)
+	}
+
+	return &MatrixNotifier{
+		ctx:         ctx,
+		client:      client,
+		homeserver:  strings.TrimSuffix(homeserver, "/"),
+		accessToken: accessToken,
+		roomID:      roomID,
+		maxRetries:  maxRetries,
+		txnPrefix:   fmt.Sprintf("gitnotifier-%d", time.Now().UnixNano()),
+	}, nil
+}
+
+type matrixMessage struct {
+	MsgType       string `json:"msgtype"`
+	Body          string `json:"body"`
+	Format        string `json:"format"`
+	FormattedBody string `json:"formatted_body"`
+}
+
+type matrixError struct {
+	ErrCode      string `json:"errcode"`
+	Error        string `json:"error"`
+	RetryAfterMs int64  `json:"retry_after_ms"`
+}
+
+func (n *MatrixNotifier) Notify(title, message, link string) error {
+	body, err := json.Marshal(matrixMessage{
+		MsgType: "m.text",
+		Body:    fmt.Sprintf("%s\n%s\n%s", title, message, link),
+		Format:  "org.matrix.custom.html",
+		FormattedBody: fmt.Sprintf(`<strong>%s</strong><br>%s<br><a href="%s">%s</a>`,
+			html.EscapeString(title), html.EscapeString(message), html.EscapeString(link), html.EscapeString(link)),
+	})
+	if err != nil {
+		return fmt.Errorf("error encoding Matrix message: %v", err)
+	}
+
+	// The transaction ID is reused across retries so the homeserver drops duplicates
+	txnID := fmt.Sprintf("%s-%d", n.txnPrefix, n.txnCounter.Add(1))
+	endpoint := fmt.Sprintf("%s/_matrix/client/v3/rooms/%s/send/m.room.message/%s",
+		n.homeserver, url.PathEscape(n.roomID), url.PathEscape(txnID))
+
+	for attempt := 0; ; attempt++ {
+		retryAfter, err := n.send(endpoint, body)
+		if err == nil {
+			return nil
+		}
+		if retryAfter == 0 || attempt == n.maxRetries {
+			return err
+		}
+		if err := ctxutil.Sleep(n.ctx, min(retryAfter, config.MaxRetryAfter)); err != nil {
+			return err
+		}
+	}
+}
+
+// send performs one attempt and returns how long to wait when the homeserver rate limited it
+func (n *MatrixNotifier) send(endpoint string, body []byte) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), n.client.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("error creating Matrix request: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+n.accessToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("error sending Matrix message: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		return 0, nil
+	}
+
+	var apiErr matrixError
+	_ = json.NewDecoder(resp.Body).Decode(&apiErr)
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		retryAfter := time.Duration(apiErr.RetryAfterMs) * time.Millisecond
+		if retryAfter <= 0 {
+			retryAfter = time.Second
+		}
+		return retryAfter, fmt.Errorf("Matrix homeserver rate limited the message")
+	case resp.StatusCode == http.StatusUnauthorized:
+		return 0, fmt.Errorf("Matrix access token was rejected. Please check MATRIX_ACCESS_TOKEN")
+	case apiErr.ErrCode != "":
+		return 0, fmt.Errorf("Matrix homeserver returned %s: %s", apiErr.ErrCode, apiErr.Error)
+	default:
+		return 0, fmt.Errorf("Matrix homeserver returned status code: %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,37 @@
+package platform
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestMatrixNotifierRateLimitWaitEndsWithContext(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		io.WriteString(w, `{"errcode": "M_LIMIT_EXCEEDED", "retry_after_ms": 3600000}`)
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	client := srv.Client()
+	client.Timeout = time.Second
+	n, err := NewMatrixNotifier(ctx, client, srv.URL, "token", "!room:example.com", 3)
+	if err != nil {
+		t.Fatalf("NewMatrixNotifier: %v", err)
+	}
+
+	start := time.Now()
+	err = n.Notify("New GitHub Issue", "#1: Crash", "https://github.com/owner/repo/issues/1")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Notify = %v, want context.DeadlineExceeded", err)
+	}
+	if waited := time.Since(start); waited > time.Second {
+		t.Errorf("Notify waited %v for an hour-long rate limit after ctx was done", waited)
+	}
+}
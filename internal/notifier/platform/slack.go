@@ -0,0 +1,86 @@
+package platform
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// SlackNotifier posts notifications to a Slack incoming webhook
+type SlackNotifier struct {
+	client     *http.Client
+	webhookURL string
+}
+
+func NewSlackNotifier(client *http.Client, webhookURL string) *SlackNotifier {
+	return &SlackNotifier{
+		client:     client,
+		webhookURL: webhookURL,
+	}
+}
+
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type slackBlock struct {
+	Type string     `json:"type"`
+	Text *slackText `json:"text,omitempty"`
+}
+
+type slackPayload struct {
+	Text   string       `json:"text"` // Fallback shown in notifications and old clients
+	Blocks []slackBlock `json:"blocks"`
+}
+
+func (n *SlackNotifier) Notify(title, message, url string) error {
+	// The message is linked since it holds the issue title for new issues
+	text := fmt.Sprintf("*%s*", slackEscape(title))
+	if message != "" {
+		text += fmt.Sprintf("\n<%s|%s>", url, slackEscape(message))
+	} else {
+		text += fmt.Sprintf("\n<%s>", url)
+	}
+
+	body, err := json.Marshal(slackPayload{
+		Text: fmt.Sprintf("%s: %s %s", title, message, url),
+		Blocks: []slackBlock{
+			{Type: "section", Text: &slackText{Type: "mrkdwn", Text: text}},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("error encoding Slack message: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), n.client.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error creating Slack request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error posting to Slack: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		// Slack explains webhook errors in a short plain-text body, e.g. "invalid_payload"
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 200))
+		return fmt.Errorf("Slack webhook returned status code %d: %s", resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	return nil
+}
+
+// slackEscape escapes the characters Slack treats as control sequences in mrkdwn text
+func slackEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
@@ -0,0 +1,82 @@
+package platform
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/smithy-go"
+)
+
+// SNSNotifier publishes notifications to an AWS SNS topic
+type SNSNotifier struct {
+	client   *sns.Client
+	topicARN string
+	timeout  time.Duration
+}
+
+// NewSNSNotifier creates an SNS notifier using the standard AWS credential
+// and region resolution (environment, shared config, instance roles)
+func NewSNSNotifier(ctx context.Context, topicARN string, timeout time.Duration) (*SNSNotifier, error) {
+	cfg, err := awsconfig.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error loading AWS configuration: %v", err)
+	}
+	if cfg.Region == "" {
+		return nil, fmt.Errorf("AWS region is not set. Please set AWS_REGION")
+	}
+
+	return &SNSNotifier{
+		client:   sns.NewFromConfig(cfg),
+		topicARN: topicARN,
+		timeout:  timeout,
+	}, nil
+}
+
+func (n *SNSNotifier) Notify(title, message, url string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), n.timeout)
+	defer cancel()
+
+	// SNS subjects are limited to 100 characters and are only used by email subscriptions
+	subject := title
+	if len(subject) > 100 {
+		subject = subject[:97] + "..."
+	}
+
+	_, err := n.client.Publish(ctx, &sns.PublishInput{
+		TopicArn: aws.String(n.topicARN),
+		Subject:  aws.String(subject),
+		Message:  aws.String(fmt.Sprintf("%s\n%s\n\n%s", title, message, url)),
+	})
+	if err != nil {
+		return snsError(err)
+	}
+	return nil
+}
+
+// snsError maps common SNS API errors to descriptive messages
+func snsError(err error) error {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return fmt.Errorf("error publishing to SNS: %v", err)
+	}
+
+	switch apiErr.ErrorCode() {
+	case "NotFound":
+		return fmt.Errorf("SNS topic not found. Please check SNS_TOPIC_ARN")
+	case "AuthorizationError":
+		return fmt.Errorf("not authorized to publish to the SNS topic: %s", apiErr.ErrorMessage())
+	case "InvalidClientTokenId", "UnrecognizedClientException", "SignatureDoesNotMatch":
+		return fmt.Errorf("AWS credentials were rejected. Please check your AWS credentials")
+	case "InvalidParameter":
+		return fmt.Errorf("SNS rejected the message: %s", apiErr.ErrorMessage())
+	case "Throttled", "ThrottlingException":
+		return fmt.Errorf("SNS publish was throttled, try again later")
+	default:
+		return fmt.Errorf("error publishing to SNS (%s): %s", apiErr.ErrorCode(), apiErr.ErrorMessage())
+	}
+}
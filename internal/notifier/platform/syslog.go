@@ -0,0 +1,30 @@
+package platform
+
+import (
+	"fmt"
+	"strings"
+)
+
+// syslogLine renders a notification as key=value text. The repository and
+// issue number are pulled out of GitHub issue URLs so they can be searched for.
+func syslogLine(title, message, url string) string {
+	var b strings.Builder
+	if repo, number, ok := issueFromURL(url); ok {
+		fmt.Fprintf(&b, "repo=%s issue=%s ", repo, number)
+	}
+	fmt.Fprintf(&b, "title=%q message=%q url=%s", title, message, url)
+	return b.String()
+}
+
+// issueFromURL extracts "owner/repo" and the number from an issue or pull request URL
+func issueFromURL(url string) (repo, number string, ok bool) {
+	path, found := strings.CutPrefix(url, "https://github.com/")
+	if !found {
+		return "", "", false
+	}
+	parts := strings.Split(path, "/")
+	if len(parts) < 4 || (parts[2] != "issues" && parts[2] != "pull") {
+		return "", "", false
+	}
+	return parts[0] + "/" + parts[1], parts[3], true
+}
@@ -0,0 +1,21 @@
+//go:build windows || plan9
+
+package platform
+
+import "log/slog"
+
+// SyslogNotifier falls back to the standard logger where there is no syslog
+type SyslogNotifier struct {
+	tag string
+}
+
+// NewSyslogNotifier ignores facility and severity since there is no syslog on this platform
+func NewSyslogNotifier(tag, facility, severity string) (*SyslogNotifier, error) {
+	slog.Warn("syslog is not available on this platform, notifications will be written to the log")
+	return &SyslogNotifier{tag: tag}, nil
+}
+
+func (n *SyslogNotifier) Notify(title, message, url string) error {
+	slog.Info(syslogLine(title, message, url), "event", "notification", "tag", n.tag)
+	return nil
+}
@@ -0,0 +1,94 @@
+//go:build !windows && !plan9
+
+package platform
+
+import (
+	"fmt"
+	"log/syslog"
+	"strings"
+)
+
+// SyslogNotifier writes notifications to the system log, which journald
+// also collects on systemd hosts
+type SyslogNotifier struct {
+	writer   *syslog.Writer
+	priority syslog.Priority
+}
+
+var syslogFacilities = map[string]syslog.Priority{
+	"user":   syslog.LOG_USER,
+	"daemon": syslog.LOG_DAEMON,
+	"local0": syslog.LOG_LOCAL0,
+	"local1": syslog.LOG_LOCAL1,
+	"local2": syslog.LOG_LOCAL2,
+	"local3": syslog.LOG_LOCAL3,
+	"local4": syslog.LOG_LOCAL4,
+	"local5": syslog.LOG_LOCAL5,
+	"local6": syslog.LOG_LOCAL6,
+	"local7": syslog.LOG_LOCAL7,
+}
+
+var syslogSeverities = map[string]syslog.Priority{
+	"emerg":   syslog.LOG_EMERG,
+	"alert":   syslog.LOG_ALERT,
+	"crit":    syslog.LOG_CRIT,
+	"err":     syslog.LOG_ERR,
+	"warning": syslog.LOG_WARNING,
+	"notice":  syslog.LOG_NOTICE,
+	"info":    syslog.LOG_INFO,
+	"debug":   syslog.LOG_DEBUG,
+}
+
+// NewSyslogNotifier connects to the local syslog daemon. facility (e.g.
+// "daemon", "local0") and severity (e.g. "notice") default to "user" and "notice".
+func NewSyslogNotifier(tag, facility, severity string) (*SyslogNotifier, error) {
+	f, ok := syslogFacilities[strings.ToLower(defaultString(facility, "user"))]
+	if !ok {
+		return nil, fmt.Errorf("unknown syslog facility %q", facility)
+	}
+	s, ok := syslogSeverities[strings.ToLower(defaultString(severity, "notice"))]
+	if !ok {
+		return nil, fmt.Errorf("unknown syslog severity %q", severity)
+	}
+
+	writer, err := syslog.New(f|s, tag)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to syslog: %v", err)
+	}
+	return &SyslogNotifier{writer: writer, priority: s}, nil
+}
+
+func (n *SyslogNotifier) Notify(title, message, url string) error {
+	line := syslogLine(title, message, url)
+
+	var err error
+	switch n.priority {
+	case syslog.LOG_EMERG:
+		err = n.writer.Emerg(line)
+	case syslog.LOG_ALERT:
+		err = n.writer.Alert(line)
+	case syslog.LOG_CRIT:
+		err = n.writer.Crit(line)
+	case syslog.LOG_ERR:
+		err = n.writer.Err(line)
+	case syslog.LOG_WARNING:
+		err = n.writer.Warning(line)
+	case syslog.LOG_INFO:
+		err = n.writer.Info(line)
+	case syslog.LOG_DEBUG:
+		err = n.writer.Debug(line)
+	default:
+		err = n.writer.Notice(line)
+	}
+	if err != nil {
+		return fmt.Errorf("error writing to syslog: %v", err)
+	}
+	return nil
+}
+
+func defaultString(s, fallback string) string {
+	if s == "" {
+		return fallback
+	}
+	return s
+}
@@ -0,0 +1,10 @@
+//go:build !windows
+
+package platform
+
+import "github.com/gen2brain/beeep"
+
+// pushSilentToast is only silent on Windows; elsewhere beeep decides
+func pushSilentToast(title, message, icon string) error {
+	return beeep.Notify(title, message, icon)
+}
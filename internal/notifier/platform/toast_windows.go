@@ -0,0 +1,33 @@
+//go:build windows
+
+package platform
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/go-toast/toast"
+)
+
+// powerShellAppID is the application beeep shows toasts under by default
+const powerShellAppID = "{1AC14E77-02E7-4E5D-B744-2EB1AE5198B7}\\WindowsPowerShell\\v1.0\\powershell.exe"
+
+// pushSilentToast shows a Windows toast notification without a sound
+func pushSilentToast(title, message, icon string) error {
+	if icon != "" {
+		if abs, err := filepath.Abs(icon); err == nil {
+			icon = abs
+		}
+	}
+	notification := toast.Notification{
+		AppID:   powerShellAppID,
+		Title:   title,
+		Message: message,
+		Icon:    icon,
+		Audio:   toast.Silent,
+	}
+	if err := notification.Push(); err != nil {
+		return fmt.Errorf("error showing toast notification: %v", err)
+	}
+	return nil
+}
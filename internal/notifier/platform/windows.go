@@ -7,12 +7,24 @@ import (
 )
 
 // WindowsNotifier implements desktop notifications for Windows
-type WindowsNotifier struct{}
+type WindowsNotifier struct {
+	icons  Icons
+	silent bool
+}
 
-func NewWindowsNotifier() *WindowsNotifier {
-	return &WindowsNotifier{}
+// NewWindowsNotifier creates a WindowsNotifier showing the icon from icons for
+// each notification's repository owner; icons may be nil. Silent toasts play
+// no sound.
+func NewWindowsNotifier(icons Icons, silent bool) *WindowsNotifier {
+	return &WindowsNotifier{icons: icons, silent: silent}
 }
 
 func (n *WindowsNotifier) Notify(title, message, url string) error {
-	return beeep.Notify(title, fmt.Sprintf("%s\n\nClick to open: %s", message, url), "")
+	message = fmt.Sprintf("%s\n\nClick to open: %s", message, url)
+	if !n.silent {
+		return beeep.Notify(title, message, n.icons.For(url))
+	}
+
+	// beeep always plays the default sound, so silent toasts are pushed directly
+	return pushSilentToast(title, message, n.icons.For(url))
 }
@@ -0,0 +1,142 @@
+package notifier
+
+import (
+	"fmt"
+	"gitnotifier/internal/notifier/platform"
+	"log/slog"
+	"sync"
+)
+
+// Replacer shows a notification that replaces an earlier one by ID
+type Replacer interface {
+	Replace(replacesID uint32, title, message, url string) (uint32, error)
+	Close(id uint32) error
+}
+
+// OpenIssues tracks the URLs of new issues that are still open, shared by
+// every service so notifiers can show how many there are
+type OpenIssues struct {
+	mu      sync.Mutex
+	urls    map[string]bool
+	changed func() // Called after an issue is closed, outside the lock
+}
+
+// NewOpenIssues creates an empty OpenIssues
+func NewOpenIssues() *OpenIssues {
+	return &OpenIssues{urls: make(map[string]bool)}
+}
+
+// Opened records a new issue as open
+func (o *OpenIssues) Opened(url string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.urls[url] = true
+}
+
+// Closed forgets an issue that was closed, transferred or deleted
+func (o *OpenIssues) Closed(url string) {
+	o.mu.Lock()
+	tracked := o.urls[url]
+	delete(o.urls, url)
+	changed := o.changed
+	o.mu.Unlock()
+
+	if tracked && changed != nil {
+		changed()
+	}
+}
+
+// Count returns the number of open new issues
+func (o *OpenIssues) Count() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return len(o.urls)
+}
+
+// ReplaceInPlaceNotifier keeps a single desktop notification showing how many
+// new issues are still open and the latest notification, updating it instead
+// of stacking toasts. It is removed once all those issues are closed.
+type ReplaceInPlaceNotifier struct {
+	replacer Replacer
+	fallback Notifier
+	open     *OpenIssues
+	mu       sync.Mutex
+	id       uint32
+	latest   NotificationMessage
+}
+
+// NewReplaceInPlaceNotifier returns a ReplaceInPlaceNotifier counting the
+// issues in open when the platform supports replacing notifications, otherwise
+// fallback unchanged. icons and sound are applied as by NewPlatformNotifier.
+func NewReplaceInPlaceNotifier(fallback Notifier, open *OpenIssues, icons platform.Icons, sound string) Notifier {
+	replacer, err := platform.NewDBusNotifier(icons, sound == platform.SoundNone)
+	if err != nil {
+		slog.Warn("Notifications can't be updated in place, sending one per issue", "event", "notification", "error", err)
+		return fallback
+	}
+	return newReplaceInPlaceNotifier(replacer, fallback, open)
+}
+
+func newReplaceInPlaceNotifier(replacer Replacer, fallback Notifier, open *OpenIssues) *ReplaceInPlaceNotifier {
+	if open == nil {
+		open = NewOpenIssues()
+	}
+	n := &ReplaceInPlaceNotifier{
+		replacer: replacer,
+		fallback: fallback,
+		open:     open,
+	}
+	open.mu.Lock()
+	open.changed = n.refresh
+	open.mu.Unlock()
+	return n
+}
+
+func (n *ReplaceInPlaceNotifier) Notify(title, message, url string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.latest = NotificationMessage{Title: title, Message: message, URL: url}
+	if err := n.show(); err != nil {
+		slog.Error("Error updating notification in place, sending separately", "event", "notification", "error", err)
+		return n.fallback.Notify(title, message, url)
+	}
+	return nil
+}
+
+// refresh updates the shown notification after the open issue count changed
+func (n *ReplaceInPlaceNotifier) refresh() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.id == 0 {
+		return
+	}
+	if n.open.Count() == 0 {
+		if err := n.replacer.Close(n.id); err != nil {
+			slog.Error("Error closing notification", "event", "notification", "error", err)
+		}
+		n.id = 0
+		return
+	}
+	if err := n.show(); err != nil {
+		slog.Error("Error updating notification in place", "event", "notification", "error", err)
+	}
+}
+
+// show replaces the notification with the current count and the latest
+// notification. Without open new issues, e.g. for a reminder, the latest
+// notification is shown as is.
+func (n *ReplaceInPlaceNotifier) show() error {
+	title, message := n.latest.Title, n.latest.Message
+	if count := n.open.Count(); count > 0 {
+		summary := fmt.Sprintf("%d open new GitHub %s", count, plural(count, "issue", "issues"))
+		title, message = summary, fmt.Sprintf("Latest: %s\n%s", title, message)
+	}
+	id, err := n.replacer.Replace(n.id, title, message, n.latest.URL)
+	if err != nil {
+		return err
+	}
+	n.id = id
+	return nil
+}
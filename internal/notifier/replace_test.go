@@ -0,0 +1,86 @@
+package notifier
+
+import (
+	"errors"
+	"testing"
+)
+
+// fakeReplacer records the notification currently shown
+type fakeReplacer struct {
+	nextID  uint32
+	shown   map[uint32]sentNotification
+	replace error
+}
+
+func (f *fakeReplacer) Replace(replacesID uint32, title, message, url string) (uint32, error) {
+	if f.replace != nil {
+		return 0, f.replace
+	}
+	if f.shown == nil {
+		f.shown = make(map[uint32]sentNotification)
+	}
+	id := replacesID
+	if id == 0 {
+		f.nextID++
+		id = f.nextID
+	}
+	f.shown[id] = sentNotification{Title: title, Message: message, URL: url}
+	return id, nil
+}
+
+func (f *fakeReplacer) Close(id uint32) error {
+	delete(f.shown, id)
+	return nil
+}
+
+func TestReplaceInPlaceNotifierCountsOpenIssues(t *testing.T) {
+	replacer, open := &fakeReplacer{}, NewOpenIssues()
+	n := newReplaceInPlaceNotifier(replacer, &recordingNotifier{}, open)
+
+	for _, number := range []string{"1", "2"} {
+		url := "https://github.com/owner/repo/issues/" + number
+		open.Opened(url)
+		if err := n.Notify("New GitHub Issue", "#"+number+": Crash", url); err != nil {
+			t.Fatalf("Notify: %v", err)
+		}
+	}
+	// A reminder for an issue already counted doesn't change the count
+	if err := n.Notify("Reminder: #1 still open after 2d", "Crash", "https://github.com/owner/repo/issues/1"); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+
+	if len(replacer.shown) != 1 {
+		t.Fatalf("%d notifications shown, want a single one", len(replacer.shown))
+	}
+	want := sentNotification{
+		Title:   "2 open new GitHub issues",
+		Message: "Latest: Reminder: #1 still open after 2d\nCrash",
+		URL:     "https://github.com/owner/repo/issues/1",
+	}
+	if got := replacer.shown[1]; got != want {
+		t.Errorf("shown %+v, want %+v", got, want)
+	}
+
+	open.Closed("https://github.com/owner/repo/issues/2")
+	if got := replacer.shown[1].Title; got != "1 open new GitHub issue" {
+		t.Errorf("after closing #2 the title is %q, want 1 open new GitHub issue", got)
+	}
+
+	open.Closed("https://github.com/owner/repo/issues/1")
+	if len(replacer.shown) != 0 {
+		t.Errorf("still showing %+v after every issue was closed", replacer.shown)
+	}
+}
+
+func TestReplaceInPlaceNotifierFallsBack(t *testing.T) {
+	fallback := &recordingNotifier{}
+	n := newReplaceInPlaceNotifier(&fakeReplacer{replace: errors.New("no notification server")}, fallback, nil)
+
+	if err := n.Notify("New GitHub Issue", "#1: Crash", "https://github.com/owner/repo/issues/1"); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	want := sentNotification{Title: "New GitHub Issue", Message: "#1: Crash", URL: "https://github.com/owner/repo/issues/1"}
+	if got := fallback.last(t); got != want {
+		t.Errorf("fallback got %+v, want %+v", got, want)
+	}
+}
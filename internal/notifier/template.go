@@ -0,0 +1,108 @@
+package notifier
+
+import (
+	"fmt"
+	"gitnotifier/internal/issue"
+	"log/slog"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// TemplateNotifier rewrites the title and message of every notification with
+// text/template templates before passing it to the wrapped notifier. Templates
+// receive a NotificationMessage, e.g. "{{.Title}} - {{.URL}}".
+type TemplateNotifier struct {
+	notifier Notifier
+	title    *template.Template
+	message  *template.Template
+}
+
+// NewTemplateNotifier creates a TemplateNotifier. An empty template leaves that
+// part unchanged. Templates that fail to parse or render a sample message are rejected.
+func NewTemplateNotifier(notifier Notifier, titleTemplate, messageTemplate string) (*TemplateNotifier, error) {
+	title, err := parseMessageTemplate("title", titleTemplate)
+	if err != nil {
+		return nil, err
+	}
+	message, err := parseMessageTemplate("message", messageTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TemplateNotifier{
+		notifier: notifier,
+		title:    title,
+		message:  message,
+	}, nil
+}
+
+func parseMessageTemplate(name, text string) (*template.Template, error) {
+	if text == "" {
+		return nil, nil
+	}
+
+	tmpl, err := template.New(name).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s template: %v", name, err)
+	}
+
+	// Unknown fields only fail on execution, so render a sample up front
+	sample := NotificationMessage{Title: "title", Message: "message", URL: "https://github.com"}
+	if _, err := render(tmpl, sample); err != nil {
+		return nil, fmt.Errorf("invalid %s template: %v", name, err)
+	}
+	return tmpl, nil
+}
+
+func (n *TemplateNotifier) Notify(title, message, url string) error {
+	msg := NotificationMessage{Title: title, Message: message, URL: url}
+	return n.notifier.Notify(n.apply(n.title, msg, title), n.apply(n.message, msg, message), url)
+}
+
+// apply renders tmpl, keeping fallback when there is no template or rendering fails
+func (n *TemplateNotifier) apply(tmpl *template.Template, msg NotificationMessage, fallback string) string {
+	if tmpl == nil {
+		return fallback
+	}
+	out, err := render(tmpl, msg)
+	if err != nil {
+		slog.Error("Error rendering template, using the original", "event", "notification", "template", tmpl.Name(), "error", err)
+		return fallback
+	}
+	return out
+}
+
+func render(tmpl *template.Template, msg NotificationMessage) (string, error) {
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, msg); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// ParseIssueTemplate parses a text/template for the message of new-issue
+// notifications, which receives the issue.Issue, e.g. "#{{.Number}} {{.Title}} ({{.State}})".
+// Like the notification templates it is rendered against a sample up front.
+func ParseIssueTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("issue").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid issue template: %v", err)
+	}
+
+	sample := issue.Issue{Number: 1, Title: "title", HTMLURL: "https://github.com", State: "open", CreatedAt: time.Now(), User: issue.User{Login: "octocat"}}
+	if err := tmpl.Execute(&strings.Builder{}, sample); err != nil {
+		return nil, fmt.Errorf("invalid issue template: %v", err)
+	}
+	return tmpl, nil
+}
+
+// formatIssueTemplate renders tmpl for issue, falling back to the default layout if rendering fails
+func formatIssueTemplate(tmpl *template.Template, issue issue.Issue) string {
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, issue); err != nil {
+		slog.Error("Error rendering issue template, using the default", "event", "notification", "issue_number", issue.Number, "error", err)
+		return formatIssueMessage(issue)
+	}
+	return sb.String()
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrSecurityAccessDenied is returned when the token may not read a security endpoint
+var ErrSecurityAccessDenied = errors.New("token lacks security_events scope or repository admin access")
+
+// Advisory is a repository security advisory or Dependabot alert
+type Advisory struct {
+	ID       string // GHSA ID for advisories, "dependabot-<number>" for alerts
+	Summary  string
+	Severity string
+	HTMLURL  string
+	Source   string // "advisory" or "dependabot"
+}
+
+// FetchSecurityAdvisories fetches the repository's most recently published security advisories
+func (r *Repository) FetchSecurityAdvisories(ctx context.Context) ([]Advisory, error) {
+	advisoriesURL := fmt.Sprintf("%s/security-advisories?sort=published&direction=desc&per_page=%d",
+		r.repoAPIURL(), r.perPage)
+
+	var result []struct {
+		GHSAID   string `json:"ghsa_id"`
+		Summary  string `json:"summary"`
+		Severity string `json:"severity"`
+		HTMLURL  string `json:"html_url"`
+	}
+	if err := r.fetchSecurityJSON(ctx, advisoriesURL, "security advisories", &result); err != nil {
+		return nil, err
+	}
+
+	advisories := make([]Advisory, 0, len(result))
+	for _, a := range result {
+		advisories = append(advisories, Advisory{
+			ID:       a.GHSAID,
+			Summary:  a.Summary,
+			Severity: a.Severity,
+			HTMLURL:  a.HTMLURL,
+			Source:   "advisory",
+		})
+	}
+	return advisories, nil
+}
+
+// FetchDependabotAlerts fetches the repository's most recent open Dependabot alerts
+func (r *Repository) FetchDependabotAlerts(ctx context.Context) ([]Advisory, error) {
+	alertsURL := fmt.Sprintf("%s/dependabot/alerts?state=open&sort=created&direction=desc&per_page=%d",
+		r.repoAPIURL(), r.perPage)
+
+	var result []struct {
+		Number           int    `json:"number"`
+		HTMLURL          string `json:"html_url"`
+		SecurityAdvisory struct {
+			Summary  string `json:"summary"`
+			Severity string `json:"severity"`
+		} `json:"security_advisory"`
+	}
+	if err := r.fetchSecurityJSON(ctx, alertsURL, "Dependabot alerts", &result); err != nil {
+		return nil, err
+	}
+
+	alerts := make([]Advisory, 0, len(result))
+	for _, a := range result {
+		alerts = append(alerts, Advisory{
+			ID:       fmt.Sprintf("dependabot-%d", a.Number),
+			Summary:  a.SecurityAdvisory.Summary,
+			Severity: a.SecurityAdvisory.Severity,
+			HTMLURL:  a.HTMLURL,
+			Source:   "dependabot",
+		})
+	}
+	return alerts, nil
+}
+
+// fetchSecurityJSON decodes a security endpoint's response into v. These
+// endpoints need extra token permissions, so 403 gets a dedicated message.
+func (r *Repository) fetchSecurityJSON(ctx context.Context, url, what string, v any) error {
+	req, err := r.newRequest(ctx, url)
+	if err != nil {
+		return err
+	}
+
+	resp, err := r.do(req)
+	if err != nil {
+		return fmt.Errorf("error fetching %s: %v", what, err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusForbidden, http.StatusNotFound:
+		return fmt.Errorf("cannot read %s (status %d): %w", what, resp.StatusCode, ErrSecurityAccessDenied)
+	default:
+		return fmt.Errorf("GitHub API returned status code: %d", resp.StatusCode)
+	}
+
+	if err := checkJSON(resp); err != nil {
+		return err
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("error decoding response: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// appTokenRefreshMargin is how long before expiry a cached installation token is replaced
+const appTokenRefreshMargin = 5 * time.Minute
+
+// AppTokenSource authenticates as a GitHub App installation. It signs a JWT
+// with the app's private key, exchanges it for an installation access token
+// and caches that token until shortly before it expires. A single
+// AppTokenSource is shared by every repository.
+type AppTokenSource struct {
+	client         *http.Client
+	apiBase        string
+	appID          string
+	installationID string
+	key            *rsa.PrivateKey
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// NewAppTokenSource creates a token source for the installation of the app
+// with the PEM encoded private key, using the API root apiBase
+func NewAppTokenSource(client *http.Client, apiBase, appID, installationID string, privateKey []byte) (*AppTokenSource, error) {
+	if appID == "" || installationID == "" {
+		return nil, fmt.Errorf("both an app ID and an installation ID are required")
+	}
+	key, err := parsePrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	return &AppTokenSource{
+		client:         client,
+		apiBase:        strings.TrimSuffix(apiBase, "/"),
+		appID:          appID,
+		installationID: installationID,
+		key:            key,
+	}, nil
+}
+
+// WithAppAuth authenticates requests with installation tokens from src
+// instead of the configured token
+func WithAppAuth(src *AppTokenSource) Option {
+	return func(r *Repository) {
+		r.app = src
+	}
+}
+
+// Token returns a valid installation access token, requesting a new one when
+// the cached token is missing or about to expire
+func (s *AppTokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != "" && time.Until(s.expires) > appTokenRefreshMargin {
+		return s.token, nil
+	}
+
+	token, expires, err := s.requestToken(ctx)
+	if err != nil {
+		return "", err
+	}
+	s.token, s.expires = token, expires
+	return token, nil
+}
+
+// requestToken exchanges a freshly signed app JWT for an installation access token
+func (s *AppTokenSource) requestToken(ctx context.Context) (string, time.Time, error) {
+	jwt, err := s.signJWT(time.Now())
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	url := fmt.Sprintf("%s/app/installations/%s/access_tokens", s.apiBase, s.installationID)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, nil)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("error creating request: %v", err)
+	}
+	req.Header.Add("Authorization", "Bearer "+jwt)
+	req.Header.Add("Accept", "application/vnd.github.v3+json")
+	req.Header.Add("User-Agent", "GitHub-Issue-Notifier")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("error requesting installation token: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return "", time.Time{}, fmt.Errorf("GitHub API returned status code %d for the installation token. Please check the app ID, installation ID and private key", resp.StatusCode)
+	}
+
+	var body struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", time.Time{}, fmt.Errorf("error decoding installation token: %v", err)
+	}
+	return body.Token, body.ExpiresAt, nil
+}
+
+// signJWT builds the RS256 JWT identifying the app. GitHub accepts at most
+// ten minutes of validity; the issue time is backdated to allow for clock drift.
+func (s *AppTokenSource) signJWT(now time.Time) (string, error) {
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	claims, _ := json.Marshal(map[string]any{
+		"iat": now.Add(-time.Minute).Unix(),
+		"exp": now.Add(9 * time.Minute).Unix(),
+		"iss": s.appID,
+	})
+
+	enc := base64.RawURLEncoding
+	unsigned := enc.EncodeToString(header) + "." + enc.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("error signing app JWT: %v", err)
+	}
+	return unsigned + "." + enc.EncodeToString(sig), nil
+}
+
+// parsePrivateKey decodes a PKCS#1 or PKCS#8 PEM encoded RSA private key, as
+// downloaded from the app settings
+func parsePrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("private key is not PEM encoded")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing private key: %v", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key is not an RSA key")
+	}
+	return key, nil
+}
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"time"
+)
+
+// retryBackoff returns the delay before retry number attempt (starting at 0).
+// It doubles from base up to maxDelay, and a random jitter of up to half the
+// delay keeps several instances from retrying in lockstep.
+func retryBackoff(attempt int, base, maxDelay time.Duration) time.Duration {
+	delay := maxDelay
+	if attempt < 32 {
+		delay = min(base<<attempt, maxDelay)
+	}
+	return delay/2 + rand.N(delay/2+1)
+}
+
+// isRetryableStatus reports whether a failed request with this status may
+// succeed when repeated. Server errors are usually transient, while other
+// client errors such as 404 or 422 will fail the same way every time. Rate
+// limit rejections are recognized separately, before the status is checked.
+func isRetryableStatus(code int) bool {
+	return code >= http.StatusInternalServerError
+}
+
+// retryDelay returns how long to wait after err before retry number attempt:
+// the duration GitHub asked for with Retry-After, otherwise the backoff from
+// config.RetryBaseDelay up to config.RetryMaxDelay
+func (r *Repository) retryDelay(err error, attempt int) time.Duration {
+	var retryAfter *RetryAfterError
+	if errors.As(err, &retryAfter) {
+		r.logger.Warn("GitHub API asked to retry later, waiting", "event", "retry_after", "repo", r.source, "wait", retryAfter.Wait.String())
+		return retryAfter.Wait
+	}
+	return retryBackoff(attempt, r.retryMin, r.retryMax)
+}
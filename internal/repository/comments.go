@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"gitnotifier/config"
+	"gitnotifier/internal/ctxutil"
+	"gitnotifier/internal/issue"
+	"net/http"
+	"path"
+	"strconv"
+	"time"
+)
+
+// Comment represents a comment on an issue or pull request
+type Comment struct {
+	ID                int64      `json:"id"`
+	Body              string     `json:"body"`
+	HTMLURL           string     `json:"html_url"`
+	IssueURL          string     `json:"issue_url"` // API URL of the commented issue
+	User              issue.User `json:"user"`
+	CreatedAt         time.Time  `json:"created_at"`
+	AuthorAssociation string     `json:"author_association"` // Author's relation to the repository, e.g. OWNER, MEMBER or NONE
+}
+
+// ByMaintainer reports whether the comment's author has write access to the
+// repository as its owner, an org member or a collaborator
+func (c Comment) ByMaintainer() bool {
+	switch c.AuthorAssociation {
+	case "OWNER", "MEMBER", "COLLABORATOR":
+		return true
+	default:
+		return false
+	}
+}
+
+// IssueNumber returns the number of the commented issue, or 0 if it can't be determined
+func (c Comment) IssueNumber() int {
+	n, _ := strconv.Atoi(path.Base(c.IssueURL))
+	return n
+}
+
+// FetchRecentComments fetches the newest issue comments across the repository
+func (r *Repository) FetchRecentComments(ctx context.Context) ([]Comment, error) {
+	url := fmt.Sprintf("%s/issues/comments?sort=created&direction=desc&per_page=%d", r.repoAPIURL(), r.perPage)
+
+	req, err := r.newRequest(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	for attempt := 0; ; attempt++ {
+		comments, retryable, err := r.doFetchComments(req)
+		if err == nil {
+			return comments, nil
+		}
+		if !retryable || attempt == config.MaxRetries {
+			return nil, err
+		}
+		if err := ctxutil.Sleep(ctx, r.retryDelay(err, attempt)); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// doFetchComments performs a single request attempt and reports whether a failure is worth retrying
+func (r *Repository) doFetchComments(req *http.Request) ([]Comment, bool, error) {
+	resp, err := r.do(req)
+	if err != nil {
+		return nil, true, fmt.Errorf("error fetching comments: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return nil, false, fmt.Errorf("GitHub API authentication failed. Please check your token")
+	}
+	if wait, ok := retryAfter(resp); ok {
+		return nil, true, &RetryAfterError{Wait: wait}
+	}
+	if isRateLimited(resp) {
+		return nil, true, ErrRateLimited
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, isRetryableStatus(resp.StatusCode), fmt.Errorf("GitHub API returned status code: %d", resp.StatusCode)
+	}
+
+	if err := checkJSON(resp); err != nil {
+		return nil, true, err
+	}
+
+	var comments []Comment
+	if err := json.NewDecoder(resp.Body).Decode(&comments); err != nil {
+		return nil, false, fmt.Errorf("error decoding response: %v", err)
+	}
+	return comments, false, nil
+}
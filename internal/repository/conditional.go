@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"gitnotifier/internal/issue"
+	"net/http"
+	"sync"
+)
+
+// cachedPage is the last successful response for a URL, kept so that a
+// 304 Not Modified can be answered without re-downloading the page
+type cachedPage struct {
+	etag         string
+	lastModified string
+	issues       []issue.Issue
+	next         string
+}
+
+// pageCache holds validators and results per request URL. Every Repository
+// has its own cache so multi-repo setups never share validators.
+type pageCache struct {
+	mu    sync.Mutex
+	pages map[string]cachedPage
+}
+
+// setConditionalHeaders adds If-None-Match and If-Modified-Since for url when
+// a previous response supplied them
+func (c *pageCache) setConditionalHeaders(req *http.Request) {
+	c.mu.Lock()
+	page, ok := c.pages[req.URL.String()]
+	c.mu.Unlock()
+	if !ok {
+		return
+	}
+	if page.etag != "" {
+		req.Header.Set("If-None-Match", page.etag)
+	}
+	if page.lastModified != "" {
+		req.Header.Set("If-Modified-Since", page.lastModified)
+	}
+}
+
+// lookup returns the cached result for url
+func (c *pageCache) lookup(url string) (cachedPage, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	page, ok := c.pages[url]
+	return page, ok
+}
+
+// store remembers resp's validators along with the decoded page. Responses
+// without either validator are not cached since they can never yield a 304.
+func (c *pageCache) store(url string, resp *http.Response, issues []issue.Issue, next string) {
+	etag := resp.Header.Get("ETag")
+	lastModified := resp.Header.Get("Last-Modified")
+	if etag == "" && lastModified == "" {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.pages == nil {
+		c.pages = make(map[string]cachedPage)
+	}
+	c.pages[url] = cachedPage{
+		etag:         etag,
+		lastModified: lastModified,
+		issues:       issues,
+		next:         next,
+	}
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestFetchLatestIssuesConditionalRequests(t *testing.T) {
+	const etag, lastModified = `W/"abc123"`, "Mon, 14 Oct 2024 09:00:00 GMT"
+	tests := []struct {
+		name      string
+		validator string // Response header of the first request
+		value     string
+		condition string // Request header that must echo the validator
+		unset     string // Request header that must not be sent
+	}{
+		{"etag", "ETag", etag, "If-None-Match", "If-Modified-Since"},
+		{"last modified", "Last-Modified", lastModified, "If-Modified-Since", "If-None-Match"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := 0
+			r := newTestRepository(t, func(w http.ResponseWriter, req *http.Request) {
+				requests++
+				if requests == 1 {
+					w.Header().Set(tt.validator, tt.value)
+					serveIssues(w, req)
+					return
+				}
+				if got := req.Header.Get(tt.condition); got != tt.value {
+					t.Errorf("%s = %q, want %q", tt.condition, got, tt.value)
+				}
+				if got := req.Header.Get(tt.unset); got != "" {
+					t.Errorf("%s = %q, want it unset", tt.unset, got)
+				}
+				w.WriteHeader(http.StatusNotModified)
+			}, "")
+
+			first, err := r.FetchLatestIssues(context.Background())
+			if err != nil {
+				t.Fatalf("FetchLatestIssues: %v", err)
+			}
+			second, err := r.FetchLatestIssues(context.Background())
+			if err != nil {
+				t.Fatalf("FetchLatestIssues after 304: %v", err)
+			}
+			if requests != 2 {
+				t.Errorf("sent %d requests, want 2", requests)
+			}
+			if len(second) != len(first) || len(second) != 2 || second[0].Number != 3 {
+				t.Errorf("after 304 got %+v, want the cached issues %+v", second, first)
+			}
+		})
+	}
+}
+
+func TestFetchLatestIssuesWithoutValidators(t *testing.T) {
+	var conditional []string
+	r := newTestRepository(t, func(w http.ResponseWriter, req *http.Request) {
+		for _, h := range []string{"If-None-Match", "If-Modified-Since"} {
+			if v := req.Header.Get(h); v != "" {
+				conditional = append(conditional, h+": "+v)
+			}
+		}
+		serveIssues(w, req)
+	}, "")
+
+	for range 2 {
+		if _, err := r.FetchLatestIssues(context.Background()); err != nil {
+			t.Fatalf("FetchLatestIssues: %v", err)
+		}
+	}
+	if len(conditional) > 0 {
+		t.Errorf("sent %v without validators from an earlier response", conditional)
+	}
+}
+
+func TestFetchLatestIssuesNotModifiedWithoutCache(t *testing.T) {
+	r := newTestRepository(t, func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusNotModified)
+	}, "")
+
+	if _, err := r.FetchLatestIssues(context.Background()); err == nil {
+		t.Error("FetchLatestIssues succeeded on a 304 with nothing cached")
+	}
+}
@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// ErrUnexpectedContentType is returned when GitHub responds with something
+// other than JSON, such as an HTML error page during an incident
+var ErrUnexpectedContentType = errors.New("GitHub API returned a non-JSON response")
+
+const contentSnippetLength = 200
+
+// checkJSON verifies the response is JSON, returning ErrUnexpectedContentType
+// with the start of the body otherwise. A missing Content-Type is accepted.
+func checkJSON(resp *http.Response) error {
+	contentType := resp.Header.Get("Content-Type")
+	if contentType == "" {
+		return nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err == nil && (mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")) {
+		return nil
+	}
+
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, contentSnippetLength))
+	return fmt.Errorf("%w (Content-Type %q): %q", ErrUnexpectedContentType, contentType,
+		strings.Join(strings.Fields(string(snippet)), " "))
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"gitnotifier/config"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestFetchLatestIssuesHTMLResponse(t *testing.T) {
+	var requests atomic.Int32
+	r := newTestRepository(t, func(w http.ResponseWriter, req *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		io.WriteString(w, "<!DOCTYPE html>\n<html><body>\n  <h1>Unicorn!</h1>  This page is taking too long to load.</body></html>")
+	}, "")
+
+	_, err := r.FetchLatestIssues(context.Background())
+	if !errors.Is(err, ErrUnexpectedContentType) {
+		t.Fatalf("error = %v, want ErrUnexpectedContentType", err)
+	}
+	if !strings.Contains(err.Error(), "<h1>Unicorn!</h1> This page") {
+		t.Errorf("error %q lacks a whitespace-collapsed snippet of the body", err)
+	}
+	if got := int(requests.Load()); got != config.MaxRetries+1 {
+		t.Errorf("sent %d requests, want %d since HTML error pages are retried", got, config.MaxRetries+1)
+	}
+}
+
+func TestCheckJSON(t *testing.T) {
+	tests := []struct {
+		contentType string
+		wantErr     bool
+	}{
+		{"application/json", false},
+		{"application/json; charset=utf-8", false},
+		{"application/vnd.github+json", false},
+		{"", false},
+		{"text/html", true},
+		{"text/plain", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.contentType, func(t *testing.T) {
+			resp := &http.Response{Header: http.Header{}, Body: io.NopCloser(strings.NewReader("body"))}
+			if tt.contentType != "" {
+				resp.Header.Set("Content-Type", tt.contentType)
+			}
+			if err := checkJSON(resp); (err != nil) != tt.wantErr {
+				t.Errorf("checkJSON = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
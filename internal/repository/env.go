@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+)
+
+// OptionsFromEnv returns the options configured in the environment for
+// repositories on the API at apiBase: pagination limits, pull requests, a
+// global cap on concurrent requests and basic or GitHub App authentication.
+// The App's installation tokens are requested through client.
+func OptionsFromEnv(client *http.Client, apiBase string) ([]Option, error) {
+	opts := []Option{WithAPIBase(apiBase)}
+
+	// Optional pagination limits
+	if n, err := strconv.Atoi(os.Getenv("PER_PAGE")); err == nil {
+		opts = append(opts, WithPerPage(n))
+	}
+	if n, err := strconv.Atoi(os.Getenv("MAX_ISSUES_PER_POLL")); err == nil {
+		opts = append(opts, WithMaxIssuesPerPoll(n))
+	}
+	if n, err := strconv.Atoi(os.Getenv("MAX_CATCH_UP_PAGES")); err == nil {
+		opts = append(opts, WithMaxCatchUpPages(n))
+	}
+
+	// Optional pull request notifications alongside issues
+	if watchPRs, _ := strconv.ParseBool(os.Getenv("WATCH_PULL_REQUESTS")); watchPRs {
+		opts = append(opts, WithPullRequests())
+	}
+
+	// Optional global cap on concurrent GitHub API requests
+	if n, err := strconv.Atoi(os.Getenv("MAX_INFLIGHT_REQUESTS")); err == nil && n > 0 {
+		opts = append(opts, WithSemaphore(NewSemaphore(n)))
+	}
+
+	// Basic auth for GitHub Enterprise setups that don't accept bearer tokens
+	if username := os.Getenv("GITHUB_USERNAME"); username != "" {
+		opts = append(opts, WithBasicAuth(username, os.Getenv("GITHUB_PASSWORD")))
+	}
+
+	// Optional GitHub App installation auth, used instead of GITHUB_TOKEN
+	if appID := os.Getenv("GITHUB_APP_ID"); appID != "" {
+		key, err := os.ReadFile(os.Getenv("GITHUB_APP_PRIVATE_KEY_PATH"))
+		if err != nil {
+			return nil, fmt.Errorf("error reading GITHUB_APP_PRIVATE_KEY_PATH: %v", err)
+		}
+		app, err := NewAppTokenSource(client, apiBase, appID, os.Getenv("GITHUB_APP_INSTALLATION_ID"), key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid GitHub App configuration: %v", err)
+		}
+		opts = append(opts, WithAppAuth(app))
+	}
+	return opts, nil
+}
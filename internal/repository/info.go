@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// RepoInfo holds repository metadata
+type RepoInfo struct {
+	FullName        string `json:"full_name"`
+	HTMLURL         string `json:"html_url"`
+	StargazersCount int    `json:"stargazers_count"`
+	DefaultBranch   string `json:"default_branch"`
+	Owner           struct {
+		Login     string `json:"login"`
+		AvatarURL string `json:"avatar_url"`
+	} `json:"owner"`
+}
+
+// FetchRepoInfo fetches the repository's metadata
+func (r *Repository) FetchRepoInfo(ctx context.Context) (*RepoInfo, error) {
+	url := r.repoAPIURL()
+
+	req, err := r.newRequest(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching repository: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GitHub API returned status code: %d", resp.StatusCode)
+	}
+
+	if err := checkJSON(resp); err != nil {
+		return nil, err
+	}
+
+	var info RepoInfo
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return nil, fmt.Errorf("error decoding response: %v", err)
+	}
+	return &info, nil
+}
+
+// notFoundError explains a 404 from the issues endpoint by probing the
+// repository itself, telling a missing repository apart from a token that
+// can't see it or its issues
+func (r *Repository) notFoundError(ctx context.Context) error {
+	notFound := fmt.Errorf("GitHub API returned status code: %d", http.StatusNotFound)
+	req, err := r.newRequest(ctx, r.repoAPIURL())
+	if err != nil {
+		return notFound
+	}
+	resp, err := r.do(req)
+	if err != nil {
+		return notFound
+	}
+	resp.Body.Close()
+
+	name := r.owner + "/" + r.repo
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		return fmt.Errorf("repository %s exists but its issues can't be read. Issues may be disabled, otherwise check that your token has the Issues permission", name)
+	case resp.StatusCode != http.StatusNotFound:
+		return notFound
+	case r.token == "" && r.app == nil && r.username == "":
+		return fmt.Errorf("repository %s was not found. If it is private, set GITHUB_TOKEN to a token with access to it", name)
+	default:
+		return fmt.Errorf("repository %s was not found or your token has no access to it. Check the token's scopes and repository access", name)
+	}
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"gitnotifier/config"
+	"gitnotifier/internal/issue"
+	"net/http"
+	"time"
+)
+
+// FetchLinkedPullRequests returns the pull requests that cross-reference an
+// issue, using its timeline. It works for issues from any repository.
+func (r *Repository) FetchLinkedPullRequests(ctx context.Context, i issue.Issue) ([]issue.LinkedPullRequest, error) {
+	if i.URL == "" {
+		return nil, fmt.Errorf("issue #%d has no API URL", i.Number)
+	}
+
+	req, err := r.newRequest(ctx, fmt.Sprintf("%s/timeline?per_page=%d", i.URL, config.MaxPerPage))
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching issue timeline: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GitHub API returned status code: %d", resp.StatusCode)
+	}
+
+	if err := checkJSON(resp); err != nil {
+		return nil, err
+	}
+
+	var events []struct {
+		Event  string `json:"event"`
+		Source struct {
+			Issue *struct {
+				Number      int    `json:"number"`
+				State       string `json:"state"`
+				PullRequest *struct {
+					MergedAt *time.Time `json:"merged_at"`
+				} `json:"pull_request"`
+			} `json:"issue"`
+		} `json:"source"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&events); err != nil {
+		return nil, fmt.Errorf("error decoding response: %v", err)
+	}
+
+	var linked []issue.LinkedPullRequest
+	seen := make(map[int]bool)
+	for _, e := range events {
+		source := e.Source.Issue
+		if e.Event != "cross-referenced" || source == nil || source.PullRequest == nil || seen[source.Number] {
+			continue
+		}
+		seen[source.Number] = true
+
+		state := source.State
+		if source.PullRequest.MergedAt != nil {
+			state = "merged"
+		}
+		linked = append(linked, issue.LinkedPullRequest{Number: source.Number, State: state})
+	}
+	return linked, nil
+}
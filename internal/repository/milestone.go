@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"gitnotifier/internal/issue"
+	"net/http"
+	"time"
+)
+
+// Milestone represents a GitHub milestone
+type Milestone struct {
+	Number  int        `json:"number"`
+	Title   string     `json:"title"`
+	DueOn   *time.Time `json:"due_on"`
+	HTMLURL string     `json:"html_url"`
+}
+
+// FetchOpenMilestones fetches the repository's open milestones that have a due date, soonest first
+func (r *Repository) FetchOpenMilestones(ctx context.Context) ([]Milestone, error) {
+	url := fmt.Sprintf("%s/milestones?state=open&sort=due_on&direction=asc&per_page=%d", r.repoAPIURL(), r.perPage)
+
+	req, err := r.newRequest(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching milestones: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GitHub API returned status code: %d", resp.StatusCode)
+	}
+
+	if err := checkJSON(resp); err != nil {
+		return nil, err
+	}
+
+	var milestones []Milestone
+	if err := json.NewDecoder(resp.Body).Decode(&milestones); err != nil {
+		return nil, fmt.Errorf("error decoding response: %v", err)
+	}
+
+	var due []Milestone
+	for _, m := range milestones {
+		if m.DueOn != nil {
+			due = append(due, m)
+		}
+	}
+	return due, nil
+}
+
+// FetchMilestoneIssues fetches the open issues in a milestone
+func (r *Repository) FetchMilestoneIssues(ctx context.Context, number int) ([]issue.Issue, error) {
+	return r.fetchIssues(ctx, r.issuesURL(fmt.Sprintf("state=open&milestone=%d&sort=created&direction=desc", number)), decodeIssueList, 0)
+}
@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ProjectItem is an item on a GitHub Projects v2 board with one field's value
+type ProjectItem struct {
+	ID         string
+	Number     int // Zero for draft issues
+	Title      string
+	URL        string
+	FieldValue string
+	// Set for iteration fields, used to resolve the current iteration
+	IterationStart    time.Time
+	IterationDuration time.Duration
+}
+
+// ProjectRepository fetches Projects v2 items through the GraphQL API
+type ProjectRepository struct {
+	*Repository
+	projectID string
+	field     string
+}
+
+// NewProjectRepository creates a client for the project with the given node ID,
+// reading the value of field for each item
+func NewProjectRepository(client *http.Client, token, projectID, field string, opts ...Option) *ProjectRepository {
+	r := NewRepository(client, "", "", token, opts...)
+	r.source = "project " + projectID
+	return &ProjectRepository{
+		Repository: r,
+		projectID:  projectID,
+		field:      field,
+	}
+}
+
+const projectItemsQuery = `query($id: ID!, $field: String!, $cursor: String) {
+  node(id: $id) {
+    ... on ProjectV2 {
+      items(first: 100, after: $cursor) {
+        pageInfo { hasNextPage endCursor }
+        nodes {
+          id
+          fieldValueByName(name: $field) {
+            ... on ProjectV2ItemFieldSingleSelectValue { name }
+            ... on ProjectV2ItemFieldTextValue { text }
+            ... on ProjectV2ItemFieldNumberValue { number }
+            ... on ProjectV2ItemFieldDateValue { date }
+            ... on ProjectV2ItemFieldIterationValue { title startDate duration }
+          }
+          content {
+            ... on Issue { number title url }
+            ... on PullRequest { number title url }
+            ... on DraftIssue { title }
+          }
+        }
+      }
+    }
+  }
+}`
+
+type projectItemsResponse struct {
+	Data struct {
+		Node *struct {
+			Items struct {
+				PageInfo struct {
+					HasNextPage bool   `json:"hasNextPage"`
+					EndCursor   string `json:"endCursor"`
+				} `json:"pageInfo"`
+				Nodes []struct {
+					ID         string `json:"id"`
+					FieldValue *struct {
+						Name      string   `json:"name"`
+						Text      string   `json:"text"`
+						Number    *float64 `json:"number"`
+						Date      string   `json:"date"`
+						Title     string   `json:"title"`
+						StartDate string   `json:"startDate"`
+						Duration  int      `json:"duration"`
+					} `json:"fieldValueByName"`
+					Content struct {
+						Number int    `json:"number"`
+						Title  string `json:"title"`
+						URL    string `json:"url"`
+					} `json:"content"`
+				} `json:"nodes"`
+			} `json:"items"`
+		} `json:"node"`
+	} `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// FetchItems fetches every item in the project along with the configured field's value
+func (r *ProjectRepository) FetchItems(ctx context.Context) ([]ProjectItem, error) {
+	var items []ProjectItem
+	var cursor *string
+
+	for {
+		resp, err := r.query(ctx, cursor)
+		if err != nil {
+			return nil, err
+		}
+		if resp.Data.Node == nil {
+			return nil, fmt.Errorf("project %s not found. Check PROJECT_ID and that the token has the read:project scope", r.projectID)
+		}
+
+		page := resp.Data.Node.Items
+		for _, n := range page.Nodes {
+			item := ProjectItem{
+				ID:     n.ID,
+				Number: n.Content.Number,
+				Title:  n.Content.Title,
+				URL:    n.Content.URL,
+			}
+			if v := n.FieldValue; v != nil {
+				switch {
+				case v.Name != "":
+					item.FieldValue = v.Name
+				case v.Text != "":
+					item.FieldValue = v.Text
+				case v.Number != nil:
+					item.FieldValue = fmt.Sprint(*v.Number)
+				case v.Date != "":
+					item.FieldValue = v.Date
+				case v.Title != "":
+					item.FieldValue = v.Title
+					if start, err := time.Parse("2006-01-02", v.StartDate); err == nil {
+						item.IterationStart = start
+						item.IterationDuration = time.Duration(v.Duration) * 24 * time.Hour
+					}
+				}
+			}
+			items = append(items, item)
+		}
+
+		if !page.PageInfo.HasNextPage {
+			return items, nil
+		}
+		next := page.PageInfo.EndCursor
+		cursor = &next
+	}
+}
+
+func (r *ProjectRepository) query(ctx context.Context, cursor *string) (*projectItemsResponse, error) {
+	body, err := json.Marshal(map[string]any{
+		"query": projectItemsQuery,
+		"variables": map[string]any{
+			"id":     r.projectID,
+			"field":  r.field,
+			"cursor": cursor,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error encoding query: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", r.graphQLURL(), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %v", err)
+	}
+	if err := r.authorize(req); err != nil {
+		return nil, err
+	}
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("User-Agent", "GitHub-Issue-Notifier")
+
+	resp, err := r.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching project items: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return nil, fmt.Errorf("GitHub API authentication failed. Please check your token")
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GitHub API returned status code: %d", resp.StatusCode)
+	}
+
+	if err := checkJSON(resp); err != nil {
+		return nil, err
+	}
+
+	var result projectItemsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("error decoding response: %v", err)
+	}
+
+	if len(result.Errors) > 0 {
+		messages := make([]string, len(result.Errors))
+		for i, e := range result.Errors {
+			messages[i] = e.Message
+		}
+		return nil, fmt.Errorf("GitHub GraphQL API error: %s", strings.Join(messages, "; "))
+	}
+	return &result, nil
+}
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gitnotifier/config"
+	"gitnotifier/internal/ctxutil"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrRateLimited is returned when GitHub rejects a request because the rate
+// limit is exhausted. Later requests wait for the reset before being sent.
+var ErrRateLimited = errors.New("GitHub API rate limit exceeded")
+
+// RetryAfterError is returned when GitHub asks for a pause before the next
+// request, typically because a secondary rate limit was hit
+type RetryAfterError struct {
+	Wait time.Duration
+}
+
+func (e *RetryAfterError) Error() string {
+	return fmt.Sprintf("GitHub API secondary rate limit exceeded, retry after %v", e.Wait)
+}
+
+// RateLimit is the GitHub API quota last reported in response headers
+type RateLimit struct {
+	Limit     int
+	Remaining int
+	Reset     time.Time // When the quota is replenished
+}
+
+// rateLimitTracker records the rate limit headers of every response
+type rateLimitTracker struct {
+	mu    sync.Mutex
+	limit RateLimit
+	known bool
+}
+
+// record updates the tracked quota from resp if it carries X-RateLimit headers
+func (t *rateLimitTracker) record(resp *http.Response) {
+	remaining, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+	reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return
+	}
+	limit, _ := strconv.Atoi(resp.Header.Get("X-RateLimit-Limit"))
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.limit = RateLimit{Limit: limit, Remaining: remaining, Reset: time.Unix(reset, 0)}
+	t.known = true
+}
+
+// RateLimit returns the quota reported by the most recent response, and false
+// if no response has reported one yet
+func (r *Repository) RateLimit() (RateLimit, bool) {
+	r.rateLimit.mu.Lock()
+	defer r.rateLimit.mu.Unlock()
+	return r.rateLimit.limit, r.rateLimit.known
+}
+
+// isRateLimited reports whether resp is a rejection due to an exhausted rate limit
+func isRateLimited(resp *http.Response) bool {
+	return (resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests) &&
+		resp.Header.Get("X-RateLimit-Remaining") == "0"
+}
+
+// retryAfter returns the delay requested by a 403 or 429 response's
+// Retry-After header, in either the seconds or the HTTP-date form, capped at
+// config.MaxRetryAfter. It returns false when there is no valid header.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+	header := resp.Header.Get("Retry-After")
+	if header == "" {
+		return 0, false
+	}
+
+	var wait time.Duration
+	if seconds, err := strconv.Atoi(header); err == nil {
+		wait = time.Duration(seconds) * time.Second
+	} else if at, err := http.ParseTime(header); err == nil {
+		wait = time.Until(at)
+	} else {
+		return 0, false
+	}
+	return min(max(wait, 0), config.MaxRetryAfter), true
+}
+
+// waitForReset blocks until the quota is replenished when the last response
+// reported none remaining, so requests aren't sent just to be rejected
+func (r *Repository) waitForReset(ctx context.Context) error {
+	limit, ok := r.RateLimit()
+	if !ok || limit.Remaining > 0 {
+		return nil
+	}
+	wait := time.Until(limit.Reset)
+	if wait <= 0 {
+		return nil
+	}
+
+	r.logger.Warn("GitHub API rate limit exhausted, waiting until it resets", "event", "rate_limit", "repo", r.source, "wait", wait.Round(time.Second).String())
+	// A second of slack so the request lands after the reset, not right before it
+	return ctxutil.Sleep(ctx, wait+time.Second)
+}
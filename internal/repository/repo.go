@@ -4,77 +4,388 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"gitnotifier/config"
+	"gitnotifier/internal/ctxutil"
 	"gitnotifier/internal/issue"
+	"io"
+	"log/slog"
 	"net/http"
+	"slices"
+	"strings"
+	"time"
 )
 
 // IssueRepository defines the interface for fetching issues
 type IssueRepository interface {
 	FetchLatestIssues(ctx context.Context) ([]issue.Issue, error)
+	FetchRecentlyUpdatedIssues(ctx context.Context) ([]issue.Issue, error)
 }
 
 // Repository implements GitHub API communication
 type Repository struct {
-	client *http.Client
-	owner  string
-	repo   string
-	token  string
+	client    *http.Client
+	owner     string
+	repo      string
+	token     string
+	perPage   int
+	maxIssues int
+	maxPages  int    // Cap on pages followed by FetchIssuesSince
+	keepPRs   bool   // Keep pull requests in fetched issues
+	source    string // Describes what is being fetched, for logging
+	username  string // Set for basic auth, used by some GitHub Enterprise setups
+	password  string
+	app       *AppTokenSource // Set when authenticating as a GitHub App installation
+	sem       *Semaphore
+	retryMin  time.Duration // First retry delay, doubled on each attempt up to retryMax
+	retryMax  time.Duration
+	cache     pageCache
+	rateLimit rateLimitTracker
+	apiBase   string // REST API root, e.g. https://api.github.com or https://host/api/v3
+	logger    *slog.Logger
+}
+
+// Option configures optional Repository behavior
+type Option func(*Repository)
+
+// WithPerPage sets the page size requested from the GitHub API
+func WithPerPage(n int) Option {
+	return func(r *Repository) {
+		if n > 0 {
+			r.perPage = min(n, config.MaxPerPage)
+		}
+	}
+}
+
+// WithMaxIssuesPerPoll caps how many issues a single fetch collects across
+// pages, including while catching up. Without it a fetch collects one page,
+// and catching up is only limited by WithMaxCatchUpPages.
+func WithMaxIssuesPerPoll(n int) Option {
+	return func(r *Repository) {
+		if n > 0 {
+			r.maxIssues = n
+		}
+	}
+}
+
+// WithMaxCatchUpPages caps how many pages FetchIssuesSince follows before
+// giving up on reaching the last seen issue
+func WithMaxCatchUpPages(n int) Option {
+	return func(r *Repository) {
+		if n > 0 {
+			r.maxPages = n
+		}
+	}
+}
+
+// WithPullRequests keeps pull requests in fetched issues instead of filtering them out
+func WithPullRequests() Option {
+	return func(r *Repository) {
+		r.keepPRs = true
+	}
+}
+
+// WithBasicAuth authenticates with username and password instead of a bearer
+// token. The token is used as the password when password is empty.
+func WithBasicAuth(username, password string) Option {
+	return func(r *Repository) {
+		r.username = username
+		r.password = password
+	}
+}
+
+// WithAPIBase sends requests to a GitHub Enterprise Server API root such as
+// "https://github.example.com/api/v3" instead of the public API
+func WithAPIBase(base string) Option {
+	return func(r *Repository) {
+		if base != "" {
+			r.apiBase = strings.TrimSuffix(base, "/")
+		}
+	}
 }
 
 // NewRepository creates a new GitHub repository client
-func NewRepository(client *http.Client, owner, repo, token string) *Repository {
-	return &Repository{
-		client: client,
-		owner:  owner,
-		repo:   repo,
-		token:  token,
+func NewRepository(client *http.Client, owner, repo, token string, opts ...Option) *Repository {
+	r := &Repository{
+		client:   client,
+		owner:    owner,
+		repo:     repo,
+		token:    token,
+		perPage:  config.DefaultPerPage,
+		maxPages: config.DefaultMaxCatchUpPages,
+		retryMin: config.RetryBaseDelay,
+		retryMax: config.RetryMaxDelay,
+		source:   owner + "/" + repo,
+		apiBase:  config.DefaultAPIBase,
+		logger:   slog.Default(),
 	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
 }
 
 // FetchLatestIssues fetches the latest issues (excluding pull requests) from GitHub
 func (r *Repository) FetchLatestIssues(ctx context.Context) ([]issue.Issue, error) {
-	// Note the addition of `is:issue` to exclude pull requests
-	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/issues?state=open&sort=created&direction=desc&per_page=10&is=issue",
-		r.owner, r.repo)
+	return r.fetchIssues(ctx, r.latestIssuesURL(), decodeIssueList, 0)
+}
+
+// FetchIssuesSince is like FetchLatestIssues but keeps following pages until
+// it reaches an issue with an ID at or below lastID, so a burst of new issues
+// larger than a page is not skipped
+func (r *Repository) FetchIssuesSince(ctx context.Context, lastID int) ([]issue.Issue, error) {
+	return r.fetchIssues(ctx, r.latestIssuesURL(), decodeIssueList, lastID)
+}
+
+func (r *Repository) latestIssuesURL() string {
+	// The issues endpoint also lists pull requests; fetchIssues filters them out
+	// unless WithPullRequests is set
+	return r.issuesURL("state=open&sort=created&direction=desc")
+}
+
+// FetchRecentlyUpdatedIssues fetches the most recently updated issues in any state,
+// which is used to detect open/closed transitions
+func (r *Repository) FetchRecentlyUpdatedIssues(ctx context.Context) ([]issue.Issue, error) {
+	return r.fetchIssues(ctx, r.issuesURL("state=all&sort=updated&direction=desc"), decodeIssueList, 0)
+}
+
+func (r *Repository) issuesURL(query string) string {
+	return fmt.Sprintf("%s/issues?%s&per_page=%d", r.repoAPIURL(), query, r.perPage)
+}
+
+// repoAPIURL returns the API URL of the repository, e.g. https://api.github.com/repos/owner/repo
+func (r *Repository) repoAPIURL() string {
+	return fmt.Sprintf("%s/repos/%s/%s", r.apiBase, r.owner, r.repo)
+}
+
+// graphQLURL returns the GraphQL endpoint, which Enterprise Server serves at
+// /api/graphql rather than under the REST root
+func (r *Repository) graphQLURL() string {
+	if root, ok := strings.CutSuffix(r.apiBase, "/api/v3"); ok {
+		return root + "/api/graphql"
+	}
+	return r.apiBase + "/graphql"
+}
+
+// decodeFunc decodes a page of issues from an API response body
+type decodeFunc func(body io.Reader) ([]issue.Issue, error)
+
+func decodeIssueList(body io.Reader) ([]issue.Issue, error) {
+	var issues []issue.Issue
+	err := json.NewDecoder(body).Decode(&issues)
+	return issues, err
+}
+
+// fetchIssues follows pagination from url until the issue cap is reached or there are no more pages.
+// When since is set it instead stops after the page that reaches an issue with an ID at or below
+// since, which relies on url being sorted newest first, or at the page cap. The issue cap then
+// only applies when WithMaxIssuesPerPoll is set.
+func (r *Repository) fetchIssues(ctx context.Context, url string, decode decodeFunc, since int) ([]issue.Issue, error) {
+	limit := r.maxIssues
+	if limit == 0 && since == 0 {
+		limit = r.perPage
+	}
+
+	var issues []issue.Issue
+	caughtUp := since == 0
+	for pages := 1; url != ""; pages++ {
+		page, next, err := r.fetchPage(ctx, url, decode)
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, page...)
+		if !caughtUp {
+			caughtUp = slices.ContainsFunc(page, func(i issue.Issue) bool { return i.ID <= since })
+		}
+
+		if limit > 0 && len(issues) >= limit {
+			// Only issues above since are lost by stopping here
+			dropped := slices.ContainsFunc(issues[limit:], func(i issue.Issue) bool { return i.ID > since })
+			if dropped || next != "" && (since == 0 || !caughtUp) {
+				r.logger.Warn("Reached the limit of issues per poll, some issues may be skipped",
+					"event", "fetch", "repo", r.source, "limit", limit)
+			}
+			issues = issues[:limit]
+			break
+		}
+		if since > 0 && caughtUp {
+			break
+		}
+		if !caughtUp && pages >= r.maxPages && next != "" {
+			r.logger.Warn("Reached the page limit before catching up, some new issues may be skipped",
+				"event", "fetch", "repo", r.source, "limit", r.maxPages)
+			break
+		}
+		url = next
+	}
+
+	// The REST issues endpoints include pull requests, which can't be excluded server-side
+	if r.keepPRs {
+		return issues, nil
+	}
+	var filteredIssues []issue.Issue
+	for _, issue := range issues {
+		// GitHub Pull Requests have a "pull_request" field
+		if issue.PullRequest == nil {
+			filteredIssues = append(filteredIssues, issue)
+		}
+	}
+
+	return filteredIssues, nil
+}
+
+// fetchPage fetches a single page of issues, retrying transient failures, and
+// returns the URL of the next page if there is one
+func (r *Repository) fetchPage(ctx context.Context, url string, decode decodeFunc) ([]issue.Issue, string, error) {
+	req, err := r.newRequest(ctx, url)
+	if err != nil {
+		return nil, "", err
+	}
+	r.cache.setConditionalHeaders(req)
+
+	for attempt := 0; ; attempt++ {
+		issues, next, retryable, err := r.doFetch(req, decode)
+		if err == nil {
+			return issues, next, nil
+		}
+		if !retryable || attempt == config.MaxRetries {
+			return nil, "", err
+		}
+		if err := ctxutil.Sleep(ctx, r.retryDelay(err, attempt)); err != nil {
+			return nil, "", err
+		}
+	}
+}
 
+// newRequest builds an authenticated GitHub API GET request
+func (r *Repository) newRequest(ctx context.Context, url string) (*http.Request, error) {
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("error creating request: %v", err)
 	}
 
-	if r.token != "" {
-		req.Header.Add("Authorization", "Bearer "+r.token)
+	if err := r.authorize(req); err != nil {
+		return nil, err
 	}
 	req.Header.Add("Accept", "application/vnd.github.v3+json")
 	req.Header.Add("User-Agent", "GitHub-Issue-Notifier")
+	return req, nil
+}
 
+// do sends req, holding a semaphore slot until the response body is closed so
+// the limit also covers reading it. It waits out an exhausted rate limit first
+// and records the limit reported by the response.
+func (r *Repository) do(req *http.Request) (*http.Response, error) {
+	if err := r.waitForReset(req.Context()); err != nil {
+		return nil, err
+	}
+	if r.sem != nil {
+		if err := r.sem.Acquire(req.Context()); err != nil {
+			return nil, err
+		}
+	}
 	resp, err := r.client.Do(req)
+	if r.sem != nil {
+		if err != nil {
+			r.sem.Release()
+		} else {
+			resp.Body = &releasingBody{ReadCloser: resp.Body, sem: r.sem}
+		}
+	}
+	if err != nil {
+		r.logger.Debug("GitHub API request failed", "event", "request", "repo", r.source, "url", req.URL.String(), "error", err)
+		return nil, err
+	}
+	r.rateLimit.record(resp)
+	r.logger.Debug("GitHub API request", "event", "request", "repo", r.source, "url", req.URL.String(),
+		"status", resp.StatusCode, "rate_limit_remaining", resp.Header.Get("X-RateLimit-Remaining"))
+	return resp, nil
+}
+
+// authorize sets basic auth when a username is configured, otherwise a GitHub
+// App installation token or the bearer token if any
+func (r *Repository) authorize(req *http.Request) error {
+	switch {
+	case r.username != "":
+		password := r.password
+		if password == "" {
+			password = r.token
+		}
+		req.SetBasicAuth(r.username, password)
+	case r.app != nil:
+		token, err := r.app.Token(req.Context())
+		if err != nil {
+			return err
+		}
+		req.Header.Add("Authorization", "Bearer "+token)
+	case r.token != "":
+		req.Header.Add("Authorization", "Bearer "+r.token)
+	}
+	return nil
+}
+
+// doFetch performs a single request attempt and reports whether a failure is worth retrying
+func (r *Repository) doFetch(req *http.Request, decode decodeFunc) ([]issue.Issue, string, bool, error) {
+	resp, err := r.do(req)
 	if err != nil {
-		return nil, fmt.Errorf("error fetching issues: %v", err)
+		return nil, "", true, fmt.Errorf("error fetching issues: %v", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusUnauthorized {
-		return nil, fmt.Errorf("GitHub API authentication failed. Please check your token")
+		return nil, "", false, fmt.Errorf("GitHub API authentication failed. Please check your token")
+	}
+
+	if wait, ok := retryAfter(resp); ok {
+		return nil, "", true, &RetryAfterError{Wait: wait}
+	}
+	// The retry waits for the reset since do holds requests until then
+	if isRateLimited(resp) {
+		return nil, "", true, ErrRateLimited
+	}
+
+	// Nothing changed since the cached response, which doesn't count against the rate limit
+	if resp.StatusCode == http.StatusNotModified {
+		if page, ok := r.cache.lookup(req.URL.String()); ok {
+			return page.issues, page.next, false, nil
+		}
+		return nil, "", false, fmt.Errorf("GitHub API returned 304 without a cached response")
+	}
+
+	// GitHub hides private repositories behind a 404, so find out which case this is
+	if resp.StatusCode == http.StatusNotFound && r.repo != "" {
+		return nil, "", false, r.notFoundError(req.Context())
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("GitHub API returned status code: %d", resp.StatusCode)
+		return nil, "", isRetryableStatus(resp.StatusCode), fmt.Errorf("GitHub API returned status code: %d", resp.StatusCode)
 	}
 
-	var issues []issue.Issue
-	if err := json.NewDecoder(resp.Body).Decode(&issues); err != nil {
-		return nil, fmt.Errorf("error decoding response: %v", err)
+	// HTML error pages are usually transient, so they are retried
+	if err := checkJSON(resp); err != nil {
+		return nil, "", true, err
 	}
 
-	// Additional check to filter out any pull requests that might have slipped through
-	var filteredIssues []issue.Issue
-	for _, issue := range issues {
-		// GitHub Pull Requests have a "pull_request" field
-		if issue.PullRequest == nil {
-			filteredIssues = append(filteredIssues, issue)
-		}
+	issues, err := decode(resp.Body)
+	if err != nil {
+		return nil, "", false, fmt.Errorf("error decoding response: %v", err)
 	}
+	next := parseNextLink(resp.Header.Get("Link"))
+	r.cache.store(req.URL.String(), resp, issues, next)
+	return issues, next, false, nil
+}
 
-	return filteredIssues, nil
+// parseNextLink extracts the rel="next" URL from a GitHub Link header
+func parseNextLink(header string) string {
+	for _, link := range strings.Split(header, ",") {
+		segments := strings.Split(link, ";")
+		if len(segments) < 2 {
+			continue
+		}
+		for _, param := range segments[1:] {
+			if strings.TrimSpace(param) == `rel="next"` {
+				return strings.Trim(strings.TrimSpace(segments[0]), "<>")
+			}
+		}
+	}
+	return ""
 }
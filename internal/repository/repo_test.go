@@ -0,0 +1,298 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"gitnotifier/config"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestRepository creates a Repository for owner/repo that talks to a test
+// server running handler and retries without noticeable delays
+func newTestRepository(t *testing.T, handler http.HandlerFunc, token string, opts ...Option) *Repository {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	opts = append([]Option{WithAPIBase(srv.URL)}, opts...)
+	r := NewRepository(srv.Client(), "owner", "repo", token, opts...)
+	r.retryMin, r.retryMax = time.Millisecond, time.Millisecond
+	r.logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	return r
+}
+
+// issuesJSON is a page from the issues endpoint with two issues and, in
+// between, a pull request
+const issuesJSON = `[
+	{"id": 3, "number": 3, "title": "Crash on start", "state": "open", "html_url": "https://github.com/owner/repo/issues/3"},
+	{"id": 2, "number": 2, "title": "Fix crash", "state": "open", "html_url": "https://github.com/owner/repo/pull/2",
+	 "pull_request": {"url": "https://api.github.com/repos/owner/repo/pulls/2"}},
+	{"id": 1, "number": 1, "title": "Typo in README", "state": "open", "html_url": "https://github.com/owner/repo/issues/1"}
+]`
+
+func serveIssues(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	io.WriteString(w, issuesJSON)
+}
+
+func TestFetchLatestIssuesFiltersPullRequests(t *testing.T) {
+	var path, query string
+	r := newTestRepository(t, func(w http.ResponseWriter, req *http.Request) {
+		path, query = req.URL.Path, req.URL.RawQuery
+		serveIssues(w, req)
+	}, "")
+
+	issues, err := r.FetchLatestIssues(context.Background())
+	if err != nil {
+		t.Fatalf("FetchLatestIssues: %v", err)
+	}
+	if path != "/repos/owner/repo/issues" {
+		t.Errorf("requested %s, want /repos/owner/repo/issues", path)
+	}
+	if want := "state=open&sort=created&direction=desc&per_page=10"; query != want {
+		t.Errorf("query = %q, want %q", query, want)
+	}
+	if len(issues) != 2 || issues[0].Number != 3 || issues[1].Number != 1 {
+		t.Fatalf("got %+v, want issues #3 and #1 without pull request #2", issues)
+	}
+}
+
+func TestFetchLatestIssuesKeepsPullRequestsWhenWatched(t *testing.T) {
+	r := newTestRepository(t, serveIssues, "", WithPullRequests())
+
+	issues, err := r.FetchLatestIssues(context.Background())
+	if err != nil {
+		t.Fatalf("FetchLatestIssues: %v", err)
+	}
+	if len(issues) != 3 || issues[1].PullRequest == nil {
+		t.Fatalf("got %+v, want all three entries including pull request #2", issues)
+	}
+}
+
+func TestFetchLatestIssuesAuthorization(t *testing.T) {
+	tests := []struct {
+		name  string
+		token string
+		opts  []Option
+		want  string
+	}{
+		{"no token", "", nil, ""},
+		{"token", "secret", nil, "Bearer secret"},
+		{"basic auth with token", "secret", []Option{WithBasicAuth("alice", "")}, "Basic YWxpY2U6c2VjcmV0"},
+		{"basic auth with password", "secret", []Option{WithBasicAuth("alice", "pass")}, "Basic YWxpY2U6cGFzcw=="},
+		{"basic auth without username", "secret", []Option{WithBasicAuth("", "pass")}, "Bearer secret"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			r := newTestRepository(t, func(w http.ResponseWriter, req *http.Request) {
+				got = req.Header.Values("Authorization")
+				serveIssues(w, req)
+			}, tt.token, tt.opts...)
+
+			if _, err := r.FetchLatestIssues(context.Background()); err != nil {
+				t.Fatalf("FetchLatestIssues: %v", err)
+			}
+			switch {
+			case tt.want == "" && len(got) > 0:
+				t.Errorf("Authorization = %q, want no header", got)
+			case tt.want != "" && (len(got) != 1 || got[0] != tt.want):
+				t.Errorf("Authorization = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetchLatestIssuesErrorStatus(t *testing.T) {
+	tests := []struct {
+		status int
+		want   string
+	}{
+		{http.StatusUnauthorized, "authentication failed"},
+		{http.StatusUnprocessableEntity, "status code: 422"},
+		{http.StatusServiceUnavailable, "status code: 503"},
+	}
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			r := newTestRepository(t, func(w http.ResponseWriter, req *http.Request) {
+				w.WriteHeader(tt.status)
+			}, "secret")
+
+			issues, err := r.FetchLatestIssues(context.Background())
+			if err == nil {
+				t.Fatalf("got %d issues, want an error", len(issues))
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %q, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetchRetriesOnlyTransientFailures(t *testing.T) {
+	past := strconv.FormatInt(time.Now().Add(-time.Minute).Unix(), 10)
+	tests := []struct {
+		name         string
+		status       int
+		header       map[string]string
+		wantRequests int
+	}{
+		{"server error", http.StatusInternalServerError, nil, config.MaxRetries + 1},
+		{"bad gateway", http.StatusBadGateway, nil, config.MaxRetries + 1},
+		{"retry after", http.StatusTooManyRequests, map[string]string{"Retry-After": "0"}, config.MaxRetries + 1},
+		{"rate limited", http.StatusForbidden, map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": past}, config.MaxRetries + 1},
+		{"forbidden", http.StatusForbidden, nil, 1},
+		{"unprocessable", http.StatusUnprocessableEntity, nil, 1},
+		{"gone", http.StatusGone, nil, 1},
+		{"unauthorized", http.StatusUnauthorized, nil, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int32
+			r := newTestRepository(t, func(w http.ResponseWriter, req *http.Request) {
+				requests.Add(1)
+				for k, v := range tt.header {
+					w.Header().Set(k, v)
+				}
+				w.WriteHeader(tt.status)
+			}, "")
+
+			if _, err := r.FetchLatestIssues(context.Background()); err == nil {
+				t.Fatal("FetchLatestIssues succeeded, want an error")
+			}
+			if got := int(requests.Load()); got != tt.wantRequests {
+				t.Errorf("sent %d requests, want %d", got, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestFetchNotFoundIsNotRetried(t *testing.T) {
+	var issueRequests atomic.Int32
+	r := newTestRepository(t, func(w http.ResponseWriter, req *http.Request) {
+		if strings.HasSuffix(req.URL.Path, "/issues") {
+			issueRequests.Add(1)
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}, "")
+
+	_, err := r.FetchLatestIssues(context.Background())
+	if err == nil {
+		t.Fatal("FetchLatestIssues succeeded, want an error")
+	}
+	if got := issueRequests.Load(); got != 1 {
+		t.Errorf("sent %d issue requests, want 1", got)
+	}
+	if !strings.Contains(err.Error(), "owner/repo") {
+		t.Errorf("error %q does not name the repository", err)
+	}
+}
+
+// paginatingServer serves total issues, newest first, perPage at a time with
+// GitHub's Link header, and counts the pages requested
+type paginatingServer struct {
+	total    int
+	pages    atomic.Int32
+	perPages []string // per_page of every request
+}
+
+func (p *paginatingServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	p.pages.Add(1)
+	perPage, _ := strconv.Atoi(req.URL.Query().Get("per_page"))
+	p.perPages = append(p.perPages, req.URL.Query().Get("per_page"))
+	page, _ := strconv.Atoi(req.URL.Query().Get("page"))
+	page = max(page, 1)
+
+	first := p.total - (page-1)*perPage
+	var items []string
+	for id := first; id > max(first-perPage, 0); id-- {
+		items = append(items, fmt.Sprintf(`{"id": %d, "number": %d, "state": "open"}`, id, id))
+	}
+	if first-perPage > 0 {
+		next := *req.URL
+		q := next.Query()
+		q.Set("page", strconv.Itoa(page+1))
+		next.RawQuery = q.Encode()
+		w.Header().Set("Link", fmt.Sprintf(`<http://%s%s>; rel="next"`, req.Host, next.RequestURI()))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	io.WriteString(w, "["+strings.Join(items, ",")+"]")
+}
+
+func TestFetchLatestIssuesPagination(t *testing.T) {
+	tests := []struct {
+		name        string
+		total       int
+		opts        []Option
+		wantIssues  int
+		wantPages   int
+		wantPerPage string
+	}{
+		{"defaults to one page", 50, nil, config.DefaultPerPage, 1, "10"},
+		{"stops at the issue cap", 50, []Option{WithPerPage(2), WithMaxIssuesPerPoll(5)}, 5, 3, "2"},
+		{"stops when pages run out", 3, []Option{WithPerPage(2), WithMaxIssuesPerPoll(10)}, 3, 2, "2"},
+		{"page size is capped", 500, []Option{WithPerPage(1000)}, config.MaxPerPage, 1, "100"},
+		{"invalid limits are ignored", 50, []Option{WithPerPage(0), WithMaxIssuesPerPoll(-1)}, config.DefaultPerPage, 1, "10"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := &paginatingServer{total: tt.total}
+			r := newTestRepository(t, srv.ServeHTTP, "", tt.opts...)
+
+			issues, err := r.FetchLatestIssues(context.Background())
+			if err != nil {
+				t.Fatalf("FetchLatestIssues: %v", err)
+			}
+			if len(issues) != tt.wantIssues {
+				t.Errorf("got %d issues, want %d", len(issues), tt.wantIssues)
+			}
+			if got := int(srv.pages.Load()); got != tt.wantPages {
+				t.Errorf("fetched %d pages, want %d", got, tt.wantPages)
+			}
+			if srv.perPages[0] != tt.wantPerPage {
+				t.Errorf("per_page = %s, want %s", srv.perPages[0], tt.wantPerPage)
+			}
+		})
+	}
+}
+
+func TestFetchIssuesSinceCatchesUp(t *testing.T) {
+	tests := []struct {
+		name       string
+		since      int
+		opts       []Option
+		wantIssues int
+		wantPages  int
+	}{
+		{"last seen issue on the first page", 48, []Option{WithPerPage(5)}, 5, 1},
+		{"follows pages past the issue cap", 38, []Option{WithPerPage(5)}, 15, 3},
+		{"gives up at the page cap", 10, []Option{WithPerPage(5), WithMaxCatchUpPages(2)}, 10, 2},
+		{"stops at the page that catches up", 43, []Option{WithPerPage(5), WithMaxIssuesPerPoll(20)}, 10, 2},
+		{"issue cap applies while catching up", 30, []Option{WithPerPage(5), WithMaxIssuesPerPoll(7)}, 7, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := &paginatingServer{total: 50}
+			r := newTestRepository(t, srv.ServeHTTP, "", tt.opts...)
+
+			issues, err := r.FetchIssuesSince(context.Background(), tt.since)
+			if err != nil {
+				t.Fatalf("FetchIssuesSince: %v", err)
+			}
+			if len(issues) != tt.wantIssues {
+				t.Errorf("got %d issues, want %d", len(issues), tt.wantIssues)
+			}
+			if got := int(srv.pages.Load()); got != tt.wantPages {
+				t.Errorf("fetched %d pages, want %d", got, tt.wantPages)
+			}
+		})
+	}
+}
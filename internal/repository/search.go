@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"gitnotifier/internal/issue"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// SearchRepository fetches issues matching a GitHub issue search query
+type SearchRepository struct {
+	*Repository
+	query string
+}
+
+// NewSearchRepository creates a client for the issues matching query
+func NewSearchRepository(client *http.Client, query, token string, opts ...Option) *SearchRepository {
+	r := NewRepository(client, "", "", token, opts...)
+	r.source = fmt.Sprintf("search %q", query)
+	return &SearchRepository{
+		Repository: r,
+		query:      query,
+	}
+}
+
+// NewOwnerRepository creates a client for the open issues across every
+// repository of a user or organization. It goes through the search API since
+// /orgs/{org}/issues only lists issues assigned to the authenticated user.
+func NewOwnerRepository(client *http.Client, owner, token string, opts ...Option) *SearchRepository {
+	r := NewSearchRepository(client, fmt.Sprintf("user:%s is:issue is:open", owner), token, opts...)
+	if r.keepPRs {
+		r.query = fmt.Sprintf("user:%s is:open", owner)
+	}
+	r.source = "owner " + owner
+	return r
+}
+
+// FetchLatestIssues fetches the most recently created issues matching the query
+func (r *SearchRepository) FetchLatestIssues(ctx context.Context) ([]issue.Issue, error) {
+	return r.fetchIssues(ctx, r.searchURL("created"), decodeSearchResults, 0)
+}
+
+// FetchIssuesSince follows pages of the query results until it reaches lastID
+func (r *SearchRepository) FetchIssuesSince(ctx context.Context, lastID int) ([]issue.Issue, error) {
+	return r.fetchIssues(ctx, r.searchURL("created"), decodeSearchResults, lastID)
+}
+
+// FetchRecentlyUpdatedIssues fetches the most recently updated issues matching the query
+func (r *SearchRepository) FetchRecentlyUpdatedIssues(ctx context.Context) ([]issue.Issue, error) {
+	return r.fetchIssues(ctx, r.searchURL("updated"), decodeSearchResults, 0)
+}
+
+func (r *SearchRepository) searchURL(sort string) string {
+	return fmt.Sprintf("%s/search/issues?q=%s&sort=%s&order=desc&per_page=%d",
+		r.apiBase, url.QueryEscape(r.query), sort, r.perPage)
+}
+
+func decodeSearchResults(body io.Reader) ([]issue.Issue, error) {
+	var result struct {
+		Items []issue.Issue `json:"items"`
+	}
+	err := json.NewDecoder(body).Decode(&result)
+	return result.Items, err
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"io"
+	"sync"
+)
+
+// Semaphore limits how many HTTP requests are in flight at once. A single
+// Semaphore is shared by every repository so the limit applies globally.
+type Semaphore struct {
+	slots chan struct{}
+}
+
+// NewSemaphore creates a Semaphore allowing n concurrent requests
+func NewSemaphore(n int) *Semaphore {
+	return &Semaphore{slots: make(chan struct{}, n)}
+}
+
+// Acquire waits for a free slot or until ctx is cancelled
+func (s *Semaphore) Acquire(ctx context.Context) error {
+	select {
+	case s.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Release frees a slot taken by Acquire
+func (s *Semaphore) Release() {
+	<-s.slots
+}
+
+// releasingBody is a response body that frees its semaphore slot when closed
+type releasingBody struct {
+	io.ReadCloser
+	sem  *Semaphore
+	once sync.Once
+}
+
+// Close closes the body and frees the slot, once however often it is called
+func (b *releasingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.sem.Release)
+	return err
+}
+
+// WithSemaphore limits the repository's concurrent requests with sem
+func WithSemaphore(sem *Semaphore) Option {
+	return func(r *Repository) {
+		r.sem = sem
+	}
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSemaphoreLimitsConcurrentRequests(t *testing.T) {
+	var mu sync.Mutex
+	running, peak := 0, 0
+	handler := func(w http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		running++
+		peak = max(peak, running)
+		mu.Unlock()
+
+		time.Sleep(20 * time.Millisecond)
+
+		mu.Lock()
+		running--
+		mu.Unlock()
+		serveIssues(w, req)
+	}
+
+	// Two repositories share the semaphore, so the limit is global
+	sem := NewSemaphore(2)
+	repos := []*Repository{
+		newTestRepository(t, handler, "", WithSemaphore(sem)),
+		newTestRepository(t, handler, "", WithSemaphore(sem)),
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for i := range 8 {
+		wg.Add(1)
+		go func(r *Repository) {
+			defer wg.Done()
+			if _, err := r.FetchLatestIssues(context.Background()); err != nil {
+				errs <- err
+			}
+		}(repos[i%len(repos)])
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("FetchLatestIssues: %v", err)
+	}
+	if peak != 2 {
+		t.Errorf("%d requests were in flight at once, want 2", peak)
+	}
+}
+
+func TestSemaphoreAcquireRespectsContext(t *testing.T) {
+	sem := NewSemaphore(1)
+	if err := sem.Acquire(context.Background()); err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := sem.Acquire(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Acquire on a full semaphore = %v, want context.DeadlineExceeded", err)
+	}
+
+	sem.Release()
+	if err := sem.Acquire(context.Background()); err != nil {
+		t.Errorf("Acquire after Release: %v", err)
+	}
+}
+
+func TestSemaphoreHeldUntilBodyClosed(t *testing.T) {
+	sem := NewSemaphore(1)
+	r := newTestRepository(t, serveIssues, "", WithSemaphore(sem))
+	req, err := r.newRequest(context.Background(), r.apiBase+"/repos/owner/repo/issues")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := r.do(req)
+	if err != nil {
+		t.Fatalf("do: %v", err)
+	}
+
+	// The slot stays taken while the body is still being read
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := sem.Acquire(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Acquire with an open body = %v, want context.DeadlineExceeded", err)
+	}
+
+	// Closing twice frees the slot only once
+	resp.Body.Close()
+	resp.Body.Close()
+	if err := sem.Acquire(context.Background()); err != nil {
+		t.Fatalf("Acquire after closing the body: %v", err)
+	}
+	if err := sem.Acquire(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("second Acquire = %v, want context.DeadlineExceeded", err)
+	}
+}
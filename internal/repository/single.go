@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gitnotifier/internal/issue"
+	"net/http"
+	"strings"
+)
+
+// ErrIssueNotFound is returned when an issue no longer exists or is inaccessible
+var ErrIssueNotFound = errors.New("issue not found")
+
+// TransferredError is returned when an issue has moved to another repository.
+// It matches ErrIssueNotFound with errors.Is since the issue is gone from this one.
+type TransferredError struct {
+	HTMLURL string // The issue's new location
+}
+
+func (e *TransferredError) Error() string {
+	return "issue was transferred to " + e.HTMLURL
+}
+
+func (e *TransferredError) Unwrap() error {
+	return ErrIssueNotFound
+}
+
+// FetchIssue fetches a single issue by number
+func (r *Repository) FetchIssue(ctx context.Context, number int) (*issue.Issue, error) {
+	url := fmt.Sprintf("%s/issues/%d", r.repoAPIURL(), number)
+	return r.fetchIssueAt(ctx, url, r.repoAPIURL())
+}
+
+// RefreshIssue fetches the current version of an issue through its API URL,
+// which works for issues from any repository
+func (r *Repository) RefreshIssue(ctx context.Context, i issue.Issue) (*issue.Issue, error) {
+	if i.URL == "" {
+		return nil, fmt.Errorf("issue #%d has no API URL", i.Number)
+	}
+	return r.fetchIssueAt(ctx, i.URL, i.RepositoryURL)
+}
+
+// fetchIssueAt fetches the issue at url, reporting a transfer if it now
+// belongs to a repository other than repositoryURL
+func (r *Repository) fetchIssueAt(ctx context.Context, url, repositoryURL string) (*issue.Issue, error) {
+	req, err := r.newRequest(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching issue: %v", err)
+	}
+	defer resp.Body.Close()
+
+	// Deleted issues return 410 Gone, transferred or hidden ones 404
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone {
+		return nil, ErrIssueNotFound
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GitHub API returned status code: %d", resp.StatusCode)
+	}
+
+	if err := checkJSON(resp); err != nil {
+		return nil, err
+	}
+
+	var i issue.Issue
+	if err := json.NewDecoder(resp.Body).Decode(&i); err != nil {
+		return nil, fmt.Errorf("error decoding response: %v", err)
+	}
+
+	// GitHub redirects requests for transferred issues to their new repository
+	if repositoryURL != "" && i.RepositoryURL != "" && !strings.EqualFold(i.RepositoryURL, repositoryURL) {
+		return nil, &TransferredError{HTMLURL: i.HTMLURL}
+	}
+	return &i, nil
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestFetchIssueDetectsTransfers(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string // %s is replaced by the test server's URL
+		wantErr  error
+		wantMove string
+	}{
+		{"open in this repository", http.StatusOK, `{"number": 2, "repository_url": "%s/repos/owner/repo"}`, nil, ""},
+		{"repository url differs in case", http.StatusOK, `{"number": 2, "repository_url": "%s/repos/Owner/Repo"}`, nil, ""},
+		{"transferred", http.StatusOK, `{"number": 7, "repository_url": "%s/repos/owner/other", "html_url": "https://github.com/owner/other/issues/7"}`,
+			ErrIssueNotFound, "https://github.com/owner/other/issues/7"},
+		{"deleted", http.StatusGone, "", ErrIssueNotFound, ""},
+		{"hidden", http.StatusNotFound, "", ErrIssueNotFound, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var base string
+			r := newTestRepository(t, func(w http.ResponseWriter, req *http.Request) {
+				if req.URL.Path != "/repos/owner/repo/issues/2" {
+					t.Errorf("requested %s, want /repos/owner/repo/issues/2", req.URL.Path)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				if tt.body != "" {
+					fmt.Fprintf(w, tt.body, base)
+				}
+			}, "")
+			base = r.apiBase
+
+			i, err := r.FetchIssue(context.Background(), 2)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("FetchIssue error = %v, want %v", err, tt.wantErr)
+			}
+			var transferred *TransferredError
+			switch {
+			case tt.wantMove != "" && (!errors.As(err, &transferred) || transferred.HTMLURL != tt.wantMove):
+				t.Errorf("FetchIssue error = %v, want a transfer to %s", err, tt.wantMove)
+			case tt.wantMove == "" && errors.As(err, &transferred):
+				t.Errorf("FetchIssue reported a transfer to %s", transferred.HTMLURL)
+			case err == nil && i.Number != 2:
+				t.Errorf("FetchIssue = %+v, want issue #2", i)
+			}
+		})
+	}
+}
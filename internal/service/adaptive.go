@@ -0,0 +1,66 @@
+package service
+
+import (
+	"gitnotifier/internal/repository"
+	"time"
+)
+
+// RateLimitSource reports the GitHub API quota seen by a repository
+type RateLimitSource interface {
+	RateLimit() (repository.RateLimit, bool)
+}
+
+// adaptiveInterval stretches the poll interval as the remaining quota drops
+// so it lasts until the reset, and shrinks it again once the quota is replenished
+type adaptiveInterval struct {
+	source        RateLimitSource
+	min, max      time.Duration
+	lastRemaining int
+	lastReset     time.Time
+}
+
+// WithAdaptiveInterval derives the poll interval from the rate limit reported
+// by source, bounded by min and max. The configured interval is used until
+// the first response reports a rate limit.
+func WithAdaptiveInterval(source RateLimitSource, min, max time.Duration) Option {
+	return func(s *Service) {
+		s.adaptive = &adaptiveInterval{source: source, min: min, max: max, lastRemaining: -1}
+	}
+}
+
+// next returns the interval until the following poll given the current quota
+func (a *adaptiveInterval) next(current time.Duration) time.Duration {
+	limit, ok := a.source.RateLimit()
+	if !ok {
+		return current
+	}
+
+	// Requests used since the previous poll, by this and any service sharing the token
+	cost := 1
+	if a.lastRemaining >= 0 && limit.Reset.Equal(a.lastReset) && a.lastRemaining > limit.Remaining {
+		cost = a.lastRemaining - limit.Remaining
+	}
+	a.lastRemaining, a.lastReset = limit.Remaining, limit.Reset
+
+	untilReset := time.Until(limit.Reset)
+	var interval time.Duration
+	switch {
+	case untilReset <= 0:
+		interval = a.min
+	case limit.Remaining <= cost:
+		interval = untilReset
+	default:
+		// Spread the remaining polls evenly over the time left in the window
+		interval = untilReset * time.Duration(cost) / time.Duration(limit.Remaining)
+	}
+	return min(max(interval, a.min), a.max)
+}
+
+// nextInterval returns the interval to wait before the next poll, logging changes
+func (s *Service) nextInterval(current time.Duration) time.Duration {
+	next := s.adaptive.next(current)
+	if next != current {
+		s.logger.Info("Adjusting poll interval", "event", "poll_interval", "from", current.String(), "to", next.String())
+	}
+	return next
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gitnotifier/internal/repository"
+)
+
+// AdvisoryFetcher fetches the current security advisories or alerts of a repository
+type AdvisoryFetcher func(ctx context.Context) ([]repository.Advisory, error)
+
+// advisoryWatch notifies about advisories that weren't present at the previous poll
+type advisoryWatch struct {
+	fetchers []AdvisoryFetcher
+	seen     map[string]bool
+}
+
+// WithAdvisoryWatch notifies about new advisories returned by any of fetchers.
+// Advisories that exist at the first poll are recorded without notifying.
+func WithAdvisoryWatch(fetchers ...AdvisoryFetcher) Option {
+	return func(s *Service) {
+		s.advisories = &advisoryWatch{fetchers: fetchers}
+	}
+}
+
+// checkAdvisories notifies about new advisories. A fetcher the token may not
+// read is dropped since every later poll would fail the same way, and the
+// watch is turned off once none are left.
+func (s *Service) checkAdvisories(ctx context.Context) error {
+	var advisories []repository.Advisory
+	var fetchers []AdvisoryFetcher
+	for i, fetch := range s.advisories.fetchers {
+		if err := s.limiter.Wait(ctx); err != nil {
+			return fmt.Errorf("rate limit error: %v", err)
+		}
+		fetched, err := fetch(ctx)
+		if errors.Is(err, repository.ErrSecurityAccessDenied) {
+			s.logger.Error("Stopped watching security advisories", "event", "advisory", "error", err)
+			continue
+		}
+		if err != nil {
+			s.advisories.fetchers = append(fetchers, s.advisories.fetchers[i:]...)
+			return err
+		}
+		fetchers = append(fetchers, fetch)
+		advisories = append(advisories, fetched...)
+	}
+	s.advisories.fetchers = fetchers
+	if len(fetchers) == 0 {
+		s.advisories = nil
+		return nil
+	}
+
+	if s.advisories.seen == nil {
+		s.advisories.seen = make(map[string]bool, len(advisories))
+		for _, a := range advisories {
+			s.advisories.seen[a.ID] = true
+		}
+		s.logger.Info("Tracking security advisories", "event", "advisory", "known", len(advisories))
+		return nil
+	}
+
+	for _, a := range advisories {
+		if s.advisories.seen[a.ID] {
+			continue
+		}
+		if err := s.issueNotifier.NotifyAdvisory(a); err != nil {
+			s.logger.Error("Error sending notification for advisory", "event", "advisory", "advisory", a.ID, "error", err)
+			continue
+		}
+		s.logger.Info("Sent notification for advisory", "event", "advisory", "source", a.Source, "advisory", a.ID, "summary", a.Summary)
+		s.advisories.seen[a.ID] = true
+	}
+	return nil
+}
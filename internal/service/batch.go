@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"gitnotifier/internal/issue"
+	"gitnotifier/internal/notifier"
+	"strings"
+)
+
+// WithBatching sends a single summary notification instead of one per issue
+// when a poll finds more than threshold new issues
+func WithBatching(threshold int) Option {
+	return func(s *Service) {
+		if threshold > 0 {
+			s.batchThreshold = threshold
+		}
+	}
+}
+
+// deliverBatch sends one summary notification for issues and reports whether
+// it was delivered or dropped while paused. Issues already notified by another
+// view are left out.
+func (s *Service) deliverBatch(ctx context.Context, issues []issue.Issue) bool {
+	var claimed []issue.Issue
+	for _, i := range issues {
+		if s.dedup != nil && !s.dedup.claim(i.HTMLURL) {
+			s.trace(i, "already notified recently by another view, leaving out of the summary")
+			continue
+		}
+		claimed = append(claimed, i)
+	}
+	if len(claimed) == 0 {
+		return true
+	}
+
+	release := func() {
+		if s.dedup != nil {
+			for _, i := range claimed {
+				s.dedup.release(i.HTMLURL)
+			}
+		}
+	}
+	if err := s.waitNotifyDelay(ctx); err != nil {
+		release()
+		return false
+	}
+
+	if s.openIssues != nil {
+		for _, i := range claimed {
+			s.openIssues.Opened(i.HTMLURL)
+		}
+	}
+	repo, url := issuesPage(claimed)
+	err := s.notifyWithRetry(ctx, func() error { return s.issueNotifier.NotifyBatch(claimed, repo, url) })
+	if errors.Is(err, notifier.ErrPaused) {
+		s.logger.Info("Notifications are paused, skipping summary", "event", "new_issue_batch", "count", len(claimed))
+		return true
+	}
+	if notifier.IsPartial(err) {
+		s.logger.Warn("Summary notification reached only some channels", "event", "new_issue_batch", "count", len(claimed), "error", err)
+		err = nil
+	}
+	if err != nil {
+		s.logger.Error("Error sending summary notification", "event", "new_issue_batch", "count", len(claimed), "error", err)
+		release()
+		return false
+	}
+	s.logger.Info("Sent summary notification", "event", "new_issue_batch", "count", len(claimed))
+	s.delivered.Add(1)
+
+	for _, i := range claimed {
+		s.trace(i, "included in a summary of %d new issues", len(claimed))
+		if s.recent != nil {
+			s.recent.add(i, s.name)
+		}
+		if s.reminders != nil {
+			s.reminders.track(i)
+		}
+	}
+	return true
+}
+
+// issuesPage returns "owner/repo" and the web URL of its issue list when all
+// issues belong to the same repository, otherwise no name and the first issue's URL
+func issuesPage(issues []issue.Issue) (repo, url string) {
+	for _, i := range issues {
+		base, _, ok := strings.Cut(i.HTMLURL, "/issues/")
+		if !ok || (url != "" && url != base+"/issues") {
+			return "", issues[0].HTMLURL
+		}
+		url = base + "/issues"
+	}
+
+	parts := strings.Split(strings.TrimSuffix(url, "/issues"), "/")
+	if len(parts) < 2 {
+		return "", url
+	}
+	return strings.Join(parts[len(parts)-2:], "/"), url
+}
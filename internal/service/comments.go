@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"gitnotifier/internal/repository"
+	"slices"
+)
+
+// CommentFetcher defines the interface for fetching recent issue comments
+type CommentFetcher interface {
+	FetchRecentComments(ctx context.Context) ([]repository.Comment, error)
+}
+
+// commentWatch notifies about new comments, tracked separately from new issues
+type commentWatch struct {
+	fetcher         CommentFetcher
+	maintainersOnly bool
+	lastCommentID   int64
+}
+
+// WithCommentWatch notifies about new issue comments. Comments made before
+// the first poll are recorded without notifying. With maintainersOnly, only
+// comments by the repository's owner, members and collaborators are notified.
+func WithCommentWatch(fetcher CommentFetcher, maintainersOnly bool) Option {
+	return func(s *Service) {
+		s.comments = &commentWatch{fetcher: fetcher, maintainersOnly: maintainersOnly, lastCommentID: -1}
+	}
+}
+
+func (s *Service) checkComments(ctx context.Context) error {
+	if err := s.limiter.Wait(ctx); err != nil {
+		return fmt.Errorf("rate limit error: %v", err)
+	}
+
+	comments, err := s.comments.fetcher.FetchRecentComments(ctx)
+	if err != nil {
+		return err
+	}
+
+	var newest int64
+	for _, c := range comments {
+		newest = max(newest, c.ID)
+	}
+
+	if s.comments.lastCommentID < 0 {
+		s.logger.Info("Tracking new comments", "event", "comment")
+		s.comments.lastCommentID = newest
+		return nil
+	}
+
+	// Oldest first so a burst of comments arrives in reading order
+	for _, c := range slices.Backward(comments) {
+		if c.ID <= s.comments.lastCommentID {
+			continue
+		}
+		if s.comments.maintainersOnly && !c.ByMaintainer() {
+			s.logger.Debug("Skipping comment by a non-maintainer", "event", "comment", "comment_id", c.ID, "author_association", c.AuthorAssociation)
+			continue
+		}
+		if err := s.issueNotifier.NotifyComment(c); err != nil {
+			s.logger.Error("Error sending notification for comment", "event", "comment", "comment_id", c.ID, "error", err)
+			continue
+		}
+		s.logger.Info("Sent notification for new comment", "event", "comment", "issue_number", c.IssueNumber(), "author", c.User.Login)
+	}
+
+	s.comments.lastCommentID = max(s.comments.lastCommentID, newest)
+	return nil
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"gitnotifier/internal/repository"
+	"testing"
+)
+
+// scriptedComments is a CommentFetcher returning one scripted slice of comments per call
+type scriptedComments struct {
+	script [][]repository.Comment
+	calls  int
+}
+
+func (s *scriptedComments) FetchRecentComments(ctx context.Context) ([]repository.Comment, error) {
+	i := min(s.calls, len(s.script)-1)
+	s.calls++
+	return s.script[i], nil
+}
+
+func TestCheckCommentsMaintainersOnly(t *testing.T) {
+	tests := []struct {
+		association string
+		maintainer  bool
+	}{
+		{"OWNER", true},
+		{"MEMBER", true},
+		{"COLLABORATOR", true},
+		{"CONTRIBUTOR", false},
+		{"FIRST_TIME_CONTRIBUTOR", false},
+		{"FIRST_TIMER", false},
+		{"MANNEQUIN", false},
+		{"NONE", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		for _, maintainersOnly := range []bool{false, true} {
+			t.Run(fmt.Sprintf("%s maintainers only %v", tt.association, maintainersOnly), func(t *testing.T) {
+				comment := repository.Comment{
+					ID:                2,
+					HTMLURL:           "https://github.com/owner/repo/issues/1#issuecomment-2",
+					IssueURL:          "https://api.github.com/repos/owner/repo/issues/1",
+					AuthorAssociation: tt.association,
+				}
+				fetcher := &scriptedComments{script: [][]repository.Comment{
+					{{ID: 1}},
+					{comment, {ID: 1}},
+				}}
+				n := &recordingNotifier{}
+				s := newTestService(&MockRepository{}, n, 100, WithCommentWatch(fetcher, maintainersOnly))
+
+				for range 2 {
+					if err := s.checkComments(context.Background()); err != nil {
+						t.Fatalf("checkComments: %v", err)
+					}
+				}
+				want := 1
+				if maintainersOnly && !tt.maintainer {
+					want = 0
+				}
+				if got := len(n.urls()); got != want {
+					t.Errorf("sent %d notifications, want %d", got, want)
+				}
+				if s.comments.lastCommentID != 2 {
+					t.Errorf("lastCommentID = %d, want 2 whether or not the comment was notified", s.comments.lastCommentID)
+				}
+			})
+		}
+	}
+}
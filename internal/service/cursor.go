@@ -0,0 +1,62 @@
+package service
+
+import (
+	"gitnotifier/internal/state"
+	"time"
+)
+
+// cursor persists the last seen issue ID so restarts neither re-notify old
+// issues nor miss ones created while the process was down
+type cursor struct {
+	store *state.Store
+	key   string // Key in the persisted state, e.g. "owner/repo"
+	saved int
+}
+
+// WithPersistedCursor loads the last seen issue ID stored under key and saves
+// it back whenever it advances
+func WithPersistedCursor(store *state.Store, key string) Option {
+	return func(s *Service) {
+		saved := store.Get().Repos[key].LastCheckID
+		if saved > 0 {
+			s.logger.Info("Resuming from the last seen issue", "event", "cursor", "key", key, "issue_id", saved)
+		}
+		s.lastCheckID = max(s.lastCheckID, saved)
+		s.cursor = &cursor{store: store, key: key, saved: saved}
+	}
+}
+
+// WithNotifiedLog remembers the issues notified in the last ttl under key in
+// store, at most size of them, and skips them after a restart. A ttl of zero
+// disables it.
+func WithNotifiedLog(store *state.Store, key string, ttl time.Duration, size int) Option {
+	return func(s *Service) {
+		if ttl <= 0 {
+			return
+		}
+		s.issueNotifier.Sent = state.NewNotifiedLog(store, key, ttl, size)
+	}
+}
+
+// saveCursor writes lastCheckID to the state file if it moved since the last save.
+// A failed write is only logged and retried after the next poll.
+func (s *Service) saveCursor() {
+	if s.lastCheckID == s.cursor.saved {
+		return
+	}
+
+	id := s.lastCheckID
+	err := s.cursor.store.Update(func(st *state.State) {
+		if st.Repos == nil {
+			st.Repos = make(map[string]state.RepoState)
+		}
+		rs := st.Repos[s.cursor.key]
+		rs.LastCheckID = id
+		st.Repos[s.cursor.key] = rs
+	})
+	if err != nil {
+		s.logger.Error("Error saving last seen issue", "event", "cursor", "key", s.cursor.key, "error", err)
+		return
+	}
+	s.cursor.saved = id
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"sync"
+	"time"
+)
+
+// Dedup suppresses repeat notifications for the same issue across services,
+// for example when an issue matches several watched queries
+type Dedup struct {
+	ttl  time.Duration
+	mu   sync.Mutex
+	seen map[string]time.Time
+}
+
+// NewDedup creates a Dedup that remembers notified issues for ttl
+func NewDedup(ttl time.Duration) *Dedup {
+	return &Dedup{
+		ttl:  ttl,
+		seen: make(map[string]time.Time),
+	}
+}
+
+// WithDedup shares d between services so each issue is only notified once per window
+func WithDedup(d *Dedup) Option {
+	return func(s *Service) {
+		s.dedup = d
+	}
+}
+
+// claim records key and reports whether it had not been claimed within the window
+func (d *Dedup) claim(key string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := time.Now()
+	for k, t := range d.seen {
+		if now.Sub(t) >= d.ttl {
+			delete(d.seen, k)
+		}
+	}
+
+	if _, ok := d.seen[key]; ok {
+		return false
+	}
+	d.seen[key] = now
+	return true
+}
+
+// release forgets key so a failed notification can be retried by another service
+func (d *Dedup) release(key string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.seen, key)
+}
@@ -0,0 +1,422 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"gitnotifier/config"
+	"gitnotifier/internal/notifier"
+	"gitnotifier/internal/repository"
+	"gitnotifier/internal/state"
+	"log/slog"
+	"os"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// PollIntervalFromEnv returns POLL_INTERVAL, a duration such as "5m" or a bare
+// number of seconds. An invalid value falls back to the default and one below
+// config.MinPollInterval is raised to it, both with a warning.
+func PollIntervalFromEnv() time.Duration {
+	v := os.Getenv("POLL_INTERVAL")
+	if v == "" {
+		return config.DefaultPollInterval
+	}
+	d, err := config.ParsePollInterval(v)
+	if err != nil {
+		slog.Warn("Invalid POLL_INTERVAL, using the default", "value", v, "default", config.DefaultPollInterval.String(), "error", err)
+		return config.DefaultPollInterval
+	}
+	if d < config.MinPollInterval {
+		slog.Warn("POLL_INTERVAL is below the minimum, using the minimum", "value", d.String(), "minimum", config.MinPollInterval.String())
+		return config.MinPollInterval
+	}
+	return d
+}
+
+// OptionsFromEnv returns the options configured in the environment that every
+// service shares: how new issues are formatted, spaced, retried, batched and
+// ordered, which ones are filtered out or deduplicated, and which state
+// changes are notified.
+func OptionsFromEnv() ([]Option, error) {
+	var opts []Option
+
+	format, err := notifier.ParseFormat(os.Getenv("NOTIFY_FORMAT"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid NOTIFY_FORMAT: %v", err)
+	}
+	if format == notifier.FormatCustomTemplate && os.Getenv("NOTIFY_TITLE_TEMPLATE") == "" && os.Getenv("NOTIFY_MESSAGE_TEMPLATE") == "" {
+		return nil, fmt.Errorf("NOTIFY_FORMAT=custom-template requires NOTIFY_TITLE_TEMPLATE or NOTIFY_MESSAGE_TEMPLATE")
+	}
+	opts = append(opts, WithFormat(format))
+
+	// Optional minimum gap between new-issue notifications, e.g. NOTIFY_MIN_INTERVAL=2s
+	if v := os.Getenv("NOTIFY_MIN_INTERVAL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid NOTIFY_MIN_INTERVAL: %v", err)
+		}
+		opts = append(opts, WithNotifyDelay(d))
+	}
+
+	// Optional number of retries for a failed new-issue notification, e.g. NOTIFY_RETRIES=0
+	if v := os.Getenv("NOTIFY_RETRIES"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid NOTIFY_RETRIES %q. Expected a number of at least 0", v)
+		}
+		opts = append(opts, WithNotifyRetries(n))
+	}
+
+	// Optional summary notification when a poll finds more than BATCH_THRESHOLD new issues
+	if batch, _ := strconv.ParseBool(os.Getenv("BATCH_NOTIFICATIONS")); batch {
+		threshold := config.DefaultBatchThreshold
+		if v := os.Getenv("BATCH_THRESHOLD"); v != "" {
+			if threshold, err = strconv.Atoi(v); err != nil || threshold < 1 {
+				return nil, fmt.Errorf("invalid BATCH_THRESHOLD %q. Expected a positive number", v)
+			}
+		}
+		opts = append(opts, WithBatching(threshold))
+	}
+
+	// Optional new-issue message template, e.g. NOTIFY_TEMPLATE="#{{.Number}} {{.Title}} ({{.State}})"
+	if text := os.Getenv("NOTIFY_TEMPLATE"); text != "" {
+		tmpl, err := notifier.ParseIssueTemplate(text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid NOTIFY_TEMPLATE: %v", err)
+		}
+		opts = append(opts, WithIssueTemplate(tmpl))
+	}
+
+	switch order := os.Getenv("NOTIFY_ORDER"); order {
+	case "", "newest-first":
+	case "oldest-first":
+		opts = append(opts, WithOldestFirst())
+	default:
+		return nil, fmt.Errorf("invalid NOTIFY_ORDER %q. Expected newest-first or oldest-first", order)
+	}
+
+	if renderMarkdown, _ := strconv.ParseBool(os.Getenv("RENDER_MARKDOWN")); renderMarkdown {
+		opts = append(opts, WithRenderMarkdown())
+	}
+
+	// Optional issue body filters
+	bodyInclude, err := compileEnvRegexp("BODY_INCLUDE")
+	if err != nil {
+		return nil, err
+	}
+	bodyExclude, err := compileEnvRegexp("BODY_EXCLUDE")
+	if err != nil {
+		return nil, err
+	}
+	if bodyInclude != nil || bodyExclude != nil {
+		opts = append(opts, WithFilter(BodyFilter(bodyInclude, bodyExclude)))
+	}
+
+	// Optional triage mode that skips issues someone has already picked up
+	if onlyUnassigned, _ := strconv.ParseBool(os.Getenv("ONLY_UNASSIGNED")); onlyUnassigned {
+		opts = append(opts, WithFilter(UnassignedFilter))
+	}
+
+	// Optional label allowlist, e.g. LABELS=bug,regression
+	if labels := config.SplitList(os.Getenv("LABELS")); len(labels) > 0 {
+		opts = append(opts, WithFilter(LabelFilter(labels)))
+	}
+
+	// Optional state-change notifications, e.g. WATCH_TRANSITIONS=open->closed.
+	// WATCH_STATE_CHANGES=true is shorthand for both closed and reopened.
+	spec := os.Getenv("WATCH_TRANSITIONS")
+	if watch, _ := strconv.ParseBool(os.Getenv("WATCH_STATE_CHANGES")); watch && spec == "" {
+		spec = "open->closed,closed->open"
+	}
+	if spec != "" {
+		transitions, err := ParseTransitions(spec)
+		if err != nil {
+			return nil, fmt.Errorf("invalid WATCH_TRANSITIONS: %v", err)
+		}
+		opts = append(opts, WithTransitions(transitions))
+	}
+
+	// Issues surfaced by several views are only notified once within DEDUP_TTL
+	dedupTTL := config.DefaultDedupTTL
+	if v := os.Getenv("DEDUP_TTL"); v != "" {
+		if dedupTTL, err = time.ParseDuration(v); err != nil {
+			return nil, fmt.Errorf("invalid DEDUP_TTL: %v", err)
+		}
+	}
+	if dedupTTL > 0 {
+		opts = append(opts, WithDedup(NewDedup(dedupTTL)))
+	}
+
+	// Optional suppression of new issues whose title repeats a recent one, shared
+	// by all services. DEDUP_TITLE_SIMILARITY below 1 also catches near-duplicates
+	if byTitle, _ := strconv.ParseBool(os.Getenv("DEDUP_BY_TITLE")); byTitle {
+		window := config.DefaultDedupTTL
+		if v := os.Getenv("DEDUP_TITLE_WINDOW"); v != "" {
+			if window, err = time.ParseDuration(v); err != nil || window <= 0 {
+				return nil, fmt.Errorf("invalid DEDUP_TITLE_WINDOW %q. Expected a positive duration", v)
+			}
+		}
+		similarity := 1.0
+		if v := os.Getenv("DEDUP_TITLE_SIMILARITY"); v != "" {
+			if similarity, err = strconv.ParseFloat(v, 64); err != nil || similarity <= 0 || similarity > 1 {
+				return nil, fmt.Errorf("invalid DEDUP_TITLE_SIMILARITY %q. Expected a number in (0, 1]", v)
+			}
+		}
+		opts = append(opts, WithTitleDedup(NewTitleDedup(window, similarity)))
+	}
+
+	// Optional cutoff for the first run, e.g. NOTIFY_SINCE=24h or NOTIFY_SINCE=2024-01-01T00:00:00Z
+	if v := os.Getenv("NOTIFY_SINCE"); v != "" {
+		since, err := parseNotifySince(v, time.Now())
+		if err != nil {
+			return nil, fmt.Errorf("invalid NOTIFY_SINCE: %v", err)
+		}
+		opts = append(opts, WithNotifySince(since))
+	}
+	return opts, nil
+}
+
+// compileEnvRegexp compiles the regular expression in env var name, returning nil when unset
+func compileEnvRegexp(name string) (*regexp.Regexp, error) {
+	pattern := os.Getenv(name)
+	if pattern == "" {
+		return nil, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %v", name, err)
+	}
+	return re, nil
+}
+
+// parseNotifySince accepts a duration before now, such as 24h, or an RFC3339 timestamp
+func parseNotifySince(v string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(v); err == nil {
+		return now.Add(-d), nil
+	}
+	since, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is neither a duration like 24h nor an RFC3339 timestamp", v)
+	}
+	return since, nil
+}
+
+// Features are the optional per-view watches configured in the environment.
+// They need the repository or search behind each service, so they are turned
+// into options per service by RepoOptions and SearchOptions.
+type Features struct {
+	notifiedTTL     time.Duration
+	notifiedSize    int
+	grace           time.Duration
+	adaptive        bool
+	minInterval     time.Duration
+	maxInterval     time.Duration
+	linkedPRs       bool
+	linkedPRsMax    int
+	starStep        int
+	mutedIssues     *NumberSet
+	watchedIssues   *NumberSet
+	transfers       bool
+	reminderAfter   time.Duration
+	comments        bool
+	maintainersOnly bool
+	milestoneLead   time.Duration
+	milestoneTitles []string
+	advisories      bool
+	dependabot      bool
+	workflows       bool
+	workflowBranch  string
+	conclusions     []string
+}
+
+// FeaturesFromEnv reads the per-view watches from the environment
+func FeaturesFromEnv() (*Features, error) {
+	f := &Features{
+		notifiedTTL:  config.DefaultNotifiedTTL,
+		notifiedSize: config.DefaultNotifiedSize,
+		minInterval:  config.MinPollInterval,
+		maxInterval:  config.MaxAdaptivePollInterval,
+		linkedPRsMax: config.DefaultLinkedPRLookups,
+		conclusions:  []string{"failure"},
+	}
+	var err error
+
+	// Issues notified within DEDUP_PERSIST_TTL are remembered in the state file
+	// by each service and not notified again after a restart
+	if v := os.Getenv("DEDUP_PERSIST_TTL"); v != "" {
+		if f.notifiedTTL, err = time.ParseDuration(v); err != nil || f.notifiedTTL < 0 {
+			return nil, fmt.Errorf("invalid DEDUP_PERSIST_TTL %q. Expected a duration", v)
+		}
+	}
+	if v := os.Getenv("DEDUP_PERSIST_SIZE"); v != "" {
+		if f.notifiedSize, err = strconv.Atoi(v); err != nil || f.notifiedSize <= 0 {
+			return nil, fmt.Errorf("invalid DEDUP_PERSIST_SIZE %q. Expected a positive number", v)
+		}
+	}
+
+	// Optional grace period before announcing new issues, e.g. NOTIFY_DELAY_GRACE=10m
+	if v := os.Getenv("NOTIFY_DELAY_GRACE"); v != "" {
+		if f.grace, err = time.ParseDuration(v); err != nil {
+			return nil, fmt.Errorf("invalid NOTIFY_DELAY_GRACE: %v", err)
+		}
+	}
+
+	// Optional poll interval derived from the remaining rate limit, between
+	// POLL_INTERVAL_MIN and POLL_INTERVAL_MAX
+	f.adaptive, _ = strconv.ParseBool(os.Getenv("POLL_INTERVAL_ADAPTIVE"))
+	if v := os.Getenv("POLL_INTERVAL_MIN"); v != "" {
+		if f.minInterval, err = time.ParseDuration(v); err != nil {
+			return nil, fmt.Errorf("invalid POLL_INTERVAL_MIN: %v", err)
+		}
+		f.minInterval = max(f.minInterval, config.MinPollInterval)
+	}
+	if v := os.Getenv("POLL_INTERVAL_MAX"); v != "" {
+		if f.maxInterval, err = time.ParseDuration(v); err != nil {
+			return nil, fmt.Errorf("invalid POLL_INTERVAL_MAX: %v", err)
+		}
+	}
+	if f.adaptive && f.maxInterval < f.minInterval {
+		return nil, fmt.Errorf("POLL_INTERVAL_MAX (%v) must not be less than POLL_INTERVAL_MIN (%v)", f.maxInterval, f.minInterval)
+	}
+
+	// Optional status of pull requests linked to new issues, at most LINKED_PRS_MAX_PER_POLL lookups per poll
+	f.linkedPRs, _ = strconv.ParseBool(os.Getenv("LINKED_PRS"))
+	if n, err := strconv.Atoi(os.Getenv("LINKED_PRS_MAX_PER_POLL")); err == nil && n > 0 {
+		f.linkedPRsMax = n
+	}
+
+	// Optional star milestone notifications for each watched repository
+	if watchStars, _ := strconv.ParseBool(os.Getenv("WATCH_STARS")); watchStars {
+		f.starStep = config.DefaultStarMilestoneStep
+		if n, err := strconv.Atoi(os.Getenv("STAR_MILESTONE_STEP")); err == nil && n > 0 {
+			f.starStep = n
+		}
+	}
+
+	// Optional issue number mute list and allowlist, e.g. MUTE_ISSUES=5,10,100-150
+	if spec := os.Getenv("MUTE_ISSUES"); spec != "" {
+		if f.mutedIssues, err = ParseNumberSet(spec); err != nil {
+			return nil, fmt.Errorf("invalid MUTE_ISSUES: %v", err)
+		}
+	}
+	if spec := os.Getenv("WATCH_ISSUES"); spec != "" {
+		if f.watchedIssues, err = ParseNumberSet(spec); err != nil {
+			return nil, fmt.Errorf("invalid WATCH_ISSUES: %v", err)
+		}
+	}
+
+	// Optional detection of open issues that are transferred or deleted
+	f.transfers, _ = strconv.ParseBool(os.Getenv("WATCH_TRANSFERS"))
+
+	// Optional reminders for announced issues that stay open, e.g. REMINDER_AFTER=24h
+	if v := os.Getenv("REMINDER_AFTER"); v != "" {
+		if f.reminderAfter, err = time.ParseDuration(v); err != nil {
+			return nil, fmt.Errorf("invalid REMINDER_AFTER: %v", err)
+		}
+	}
+
+	// Optional notifications for new comments on the watched repositories' issues
+	f.comments, _ = strconv.ParseBool(os.Getenv("WATCH_COMMENTS"))
+	f.maintainersOnly, _ = strconv.ParseBool(os.Getenv("MAINTAINER_ONLY"))
+
+	// Optional reminders for open issues whose milestone is due within MILESTONE_LEAD_TIME, e.g. 72h
+	if v := os.Getenv("MILESTONE_LEAD_TIME"); v != "" {
+		if f.milestoneLead, err = time.ParseDuration(v); err != nil || f.milestoneLead <= 0 {
+			return nil, fmt.Errorf("invalid MILESTONE_LEAD_TIME %q. Expected a positive duration", v)
+		}
+	}
+	f.milestoneTitles = config.SplitList(os.Getenv("MILESTONES"))
+
+	// Optional security advisory watch, plus Dependabot alerts with WATCH_DEPENDABOT_ALERTS
+	f.advisories, _ = strconv.ParseBool(os.Getenv("WATCH_ADVISORIES"))
+	f.dependabot, _ = strconv.ParseBool(os.Getenv("WATCH_DEPENDABOT_ALERTS"))
+
+	// Optional GitHub Actions watch on each repository's default branch (or WORKFLOW_BRANCH)
+	f.workflows, _ = strconv.ParseBool(os.Getenv("WATCH_WORKFLOWS"))
+	f.workflowBranch = os.Getenv("WORKFLOW_BRANCH")
+	if v := os.Getenv("WORKFLOW_CONCLUSIONS"); v != "" {
+		f.conclusions = config.SplitList(v)
+	}
+	return f, nil
+}
+
+// FiltersNumbers reports whether MUTE_ISSUES or WATCH_ISSUES is set. Issue
+// numbers are per repository, so they only make sense with a single one.
+func (f *Features) FiltersNumbers() bool {
+	return f.mutedIssues != nil || f.watchedIssues != nil
+}
+
+// RepoOptions returns the options of the service watching r, which persists
+// its state in store under key. Without WORKFLOW_BRANCH the workflow watch
+// looks up the repository's default branch.
+func (f *Features) RepoOptions(ctx context.Context, r *repository.Repository, store *state.Store, key string) ([]Option, error) {
+	opts := f.viewOptions(r, r, store, key)
+	if f.starStep > 0 {
+		opts = append(opts, WithStarMilestones(r, f.starStep, store, key))
+	}
+	if f.FiltersNumbers() {
+		opts = append(opts, WithFilter(NumberFilter(f.mutedIssues, f.watchedIssues)))
+	}
+	if f.transfers {
+		opts = append(opts, WithVanishedDetection(r))
+	}
+	if f.reminderAfter != 0 {
+		opts = append(opts, WithReminders(r, f.reminderAfter))
+	}
+	if f.comments {
+		opts = append(opts, WithCommentWatch(r, f.maintainersOnly))
+	}
+	if f.milestoneLead > 0 {
+		opts = append(opts, WithMilestoneDeadlines(r, f.milestoneLead, f.milestoneTitles, store, key))
+	}
+	if f.advisories {
+		fetchers := []AdvisoryFetcher{r.FetchSecurityAdvisories}
+		if f.dependabot {
+			fetchers = append(fetchers, r.FetchDependabotAlerts)
+		}
+		opts = append(opts, WithAdvisoryWatch(fetchers...))
+	}
+	if f.workflows {
+		branch := f.workflowBranch
+		if branch == "" {
+			info, err := r.FetchRepoInfo(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("error looking up the default branch of %s, set WORKFLOW_BRANCH: %v", key, err)
+			}
+			branch = info.DefaultBranch
+		}
+		opts = append(opts, WithWorkflowWatch(r, branch, f.conclusions))
+	}
+	return opts, nil
+}
+
+// SearchOptions returns the options of a service watching the search r, for
+// a user or org or a QUERIES entry, which persists its state in store under
+// key. Held issues are re-checked with refresher.
+func (f *Features) SearchOptions(r *repository.SearchRepository, refresher IssueRefresher, store *state.Store, key string) []Option {
+	return f.viewOptions(r, refresher, store, key)
+}
+
+// viewSource is what the features shared by every kind of view need
+type viewSource interface {
+	LinkedPullRequestFetcher
+	RateLimitSource
+}
+
+func (f *Features) viewOptions(src viewSource, refresher IssueRefresher, store *state.Store, key string) []Option {
+	opts := []Option{
+		WithPersistedCursor(store, key),
+		WithNotifiedLog(store, key, f.notifiedTTL, f.notifiedSize),
+	}
+	if f.grace > 0 {
+		opts = append(opts, WithGracePeriod(refresher, f.grace))
+	}
+	if f.linkedPRs {
+		opts = append(opts, WithLinkedPullRequests(src, f.linkedPRsMax))
+	}
+	if f.adaptive {
+		opts = append(opts, WithAdaptiveInterval(src, f.minInterval, f.maxInterval))
+	}
+	return opts
+}
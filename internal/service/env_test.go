@@ -0,0 +1,83 @@
+package service
+
+import (
+	"gitnotifier/config"
+	"testing"
+	"time"
+)
+
+func TestPollIntervalFromEnv(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", config.DefaultPollInterval},
+		{"10m", 10 * time.Minute},
+		{"120", 2 * time.Minute},
+		{"10s", config.MinPollInterval},
+		{"soon", config.DefaultPollInterval},
+	}
+	for _, tt := range tests {
+		t.Setenv("POLL_INTERVAL", tt.value)
+		if got := PollIntervalFromEnv(); got != tt.want {
+			t.Errorf("POLL_INTERVAL=%q gives %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestOptionsFromEnvRejectsInvalidValues(t *testing.T) {
+	for _, env := range [][2]string{
+		{"NOTIFY_FORMAT", "fancy"},
+		{"NOTIFY_FORMAT", "custom-template"},
+		{"NOTIFY_RETRIES", "-1"},
+		{"NOTIFY_ORDER", "random"},
+		{"BODY_INCLUDE", "("},
+		{"WATCH_TRANSITIONS", "open->sideways"},
+		{"NOTIFY_SINCE", "yesterday"},
+	} {
+		t.Run(env[0]+"="+env[1], func(t *testing.T) {
+			t.Setenv(env[0], env[1])
+			if _, err := OptionsFromEnv(); err == nil {
+				t.Error("OptionsFromEnv succeeded")
+			}
+		})
+	}
+}
+
+func TestFeaturesFromEnv(t *testing.T) {
+	t.Setenv("POLL_INTERVAL_ADAPTIVE", "true")
+	t.Setenv("POLL_INTERVAL_MIN", "10m")
+	t.Setenv("POLL_INTERVAL_MAX", "5m")
+	if _, err := FeaturesFromEnv(); err == nil {
+		t.Error("FeaturesFromEnv accepted POLL_INTERVAL_MAX below POLL_INTERVAL_MIN")
+	}
+
+	t.Setenv("POLL_INTERVAL_ADAPTIVE", "false")
+	t.Setenv("MUTE_ISSUES", "5,10-12")
+	f, err := FeaturesFromEnv()
+	if err != nil {
+		t.Fatalf("FeaturesFromEnv: %v", err)
+	}
+	if !f.FiltersNumbers() {
+		t.Error("FiltersNumbers = false with MUTE_ISSUES set")
+	}
+}
+
+func TestParseNotifySince(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		{"24h", now.Add(-24 * time.Hour)},
+		{"2024-01-01T00:00:00Z", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		if got, err := parseNotifySince(tt.value, now); err != nil || !got.Equal(tt.want) {
+			t.Errorf("parseNotifySince(%q) = %v, %v; want %v", tt.value, got, err, tt.want)
+		}
+	}
+	if _, err := parseNotifySince("last week", now); err == nil {
+		t.Error("parseNotifySince accepted last week")
+	}
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"fmt"
+	"gitnotifier/config"
+	"gitnotifier/internal/issue"
+	"regexp"
+	"strings"
+)
+
+// Filter decides whether a new issue should be notified. When it rejects an
+// issue it returns a short reason that is reported by --trace-issue.
+type Filter func(issue issue.Issue) (ok bool, reason string)
+
+// WithFilter adds a filter that new issues must pass before being notified.
+// Rejected issues are still marked as seen.
+func WithFilter(f Filter) Option {
+	return func(s *Service) {
+		s.filters = append(s.filters, f)
+	}
+}
+
+// BodyFilter matches the start of the issue body against include and exclude
+// patterns; either may be nil. Only the first config.MaxBodyScanLength bytes
+// are scanned since bodies can be very large.
+func BodyFilter(include, exclude *regexp.Regexp) Filter {
+	return func(issue issue.Issue) (bool, string) {
+		body := issue.Body
+		if len(body) > config.MaxBodyScanLength {
+			body = body[:config.MaxBodyScanLength]
+		}
+
+		if include != nil && !include.MatchString(body) {
+			return false, fmt.Sprintf("body does not match BODY_INCLUDE %q", include)
+		}
+		if exclude != nil && exclude.MatchString(body) {
+			return false, fmt.Sprintf("body matches BODY_EXCLUDE %q", exclude)
+		}
+		return true, ""
+	}
+}
+
+// UnassignedFilter rejects issues that already have an assignee
+func UnassignedFilter(issue issue.Issue) (bool, string) {
+	if len(issue.Assignees) > 0 {
+		return false, fmt.Sprintf("already assigned to %s", issue.Assignees[0].Login)
+	}
+	return true, ""
+}
+
+// LabelFilter only accepts issues with at least one of the given labels.
+// Label names are compared case-insensitively, as GitHub does.
+func LabelFilter(labels []string) Filter {
+	return func(issue issue.Issue) (bool, string) {
+		for _, l := range issue.Labels {
+			for _, want := range labels {
+				if strings.EqualFold(l.Name, want) {
+					return true, ""
+				}
+			}
+		}
+		return false, fmt.Sprintf("has none of the LABELS %s", strings.Join(labels, ", "))
+	}
+}
+
+// passesFilters runs the configured filters and returns the first rejection reason
+func (s *Service) passesFilters(issue issue.Issue) (bool, string) {
+	for _, f := range s.filters {
+		if ok, reason := f(issue); !ok {
+			return false, reason
+		}
+	}
+	return true, ""
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"gitnotifier/config"
+	"gitnotifier/internal/issue"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// withBody returns issue with the given body
+func withBody(i issue.Issue, body string) issue.Issue {
+	i.Body = body
+	return i
+}
+
+func TestBodyFilter(t *testing.T) {
+	include := regexp.MustCompile(`(?i)production|outage`)
+	exclude := regexp.MustCompile(`(?i)staging`)
+	padding := strings.Repeat("x", config.MaxBodyScanLength)
+
+	tests := []struct {
+		name             string
+		include, exclude *regexp.Regexp
+		body             string
+		want             bool
+	}{
+		{"include matches", include, nil, "The API is down in Production", true},
+		{"include does not match", include, nil, "Typo in the README", false},
+		{"empty body does not match include", include, nil, "", false},
+		{"exclude matches", nil, exclude, "Only seen on staging", false},
+		{"exclude does not match", nil, exclude, "Outage in production", true},
+		{"both, excluded", include, exclude, "Outage on staging", false},
+		{"both, included", include, exclude, "Outage in eu-west", true},
+		{"include beyond the scanned length", include, nil, padding + "outage", false},
+		{"exclude beyond the scanned length", nil, exclude, padding + "staging", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, reason := BodyFilter(tt.include, tt.exclude)(withBody(testIssue(1), tt.body))
+			if ok != tt.want {
+				t.Errorf("BodyFilter = %v (%s), want %v", ok, reason, tt.want)
+			}
+			if !ok && reason == "" {
+				t.Error("rejected without a reason")
+			}
+		})
+	}
+}
+
+func TestCheckForNewIssuesAppliesBodyFilter(t *testing.T) {
+	fetched := []issue.Issue{
+		withBody(testIssue(3), "Checkout is down, full outage"),
+		withBody(testIssue(2), "Button is misaligned"),
+		withBody(testIssue(1), "Errors in production since the deploy"),
+	}
+	n := &recordingNotifier{}
+	filter := BodyFilter(regexp.MustCompile(`outage|production`), nil)
+	s := newTestService(&MockRepository{Latest: [][]issue.Issue{fetched}}, n, 100, WithFilter(filter))
+
+	if err := s.checkForNewIssues(context.Background()); err != nil {
+		t.Fatalf("checkForNewIssues: %v", err)
+	}
+	if got, want := n.urls(), []string{issueURL(3), issueURL(1)}; !slices.Equal(got, want) {
+		t.Errorf("notified %v, want %v", got, want)
+	}
+	// The filtered issue is still marked as seen
+	if s.lastCheckID != 103 {
+		t.Errorf("lastCheckID = %d, want 103", s.lastCheckID)
+	}
+}
+
+func TestCheckForNewIssuesOnlyUnassigned(t *testing.T) {
+	fetched := testIssues(1, 2, 3, 4)
+	fetched[0].Assignees = []issue.User{{Login: "alice"}}                 // #4
+	fetched[2].Assignees = []issue.User{{Login: "bob"}, {Login: "carol"}} // #2
+	fetched[3].Assignees = []issue.User{}                                 // #1, empty but present
+	n := &recordingNotifier{}
+	s := newTestService(&MockRepository{Latest: [][]issue.Issue{fetched}}, n, 100, WithFilter(UnassignedFilter))
+
+	if err := s.checkForNewIssues(context.Background()); err != nil {
+		t.Fatalf("checkForNewIssues: %v", err)
+	}
+	if got, want := n.urls(), []string{issueURL(3), issueURL(1)}; !slices.Equal(got, want) {
+		t.Errorf("notified %v, want only the unassigned issues %v", got, want)
+	}
+	if s.lastCheckID != 104 {
+		t.Errorf("lastCheckID = %d, want 104 so assigned issues are not checked again", s.lastCheckID)
+	}
+}
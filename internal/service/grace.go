@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gitnotifier/internal/issue"
+	"gitnotifier/internal/repository"
+	"time"
+)
+
+// IssueRefresher defines the interface for re-fetching an issue's current state
+type IssueRefresher interface {
+	RefreshIssue(ctx context.Context, i issue.Issue) (*issue.Issue, error)
+}
+
+// gracePeriod holds new issues before notifying so that ones closed or deleted
+// straight away, typically by bots, are never announced
+type gracePeriod struct {
+	refresher IssueRefresher
+	period    time.Duration
+	held      map[int]heldIssue // Keyed by issue ID
+	// Measure the period from the issue's creation instead of when it was
+	// first seen, for runs that don't stay up for the whole period
+	fromCreated bool
+}
+
+type heldIssue struct {
+	issue  issue.Issue
+	seenAt time.Time
+}
+
+// WithGracePeriod waits period after first seeing a new issue, then re-checks
+// it and only notifies if it is still open. Issues are re-checked on the first
+// poll after the period has passed, and the persisted cursor stays below them
+// until then so a restart holds them again instead of losing them.
+func WithGracePeriod(refresher IssueRefresher, period time.Duration) Option {
+	return func(s *Service) {
+		if period <= 0 {
+			return
+		}
+		s.grace = &gracePeriod{
+			refresher: refresher,
+			period:    period,
+			held:      make(map[int]heldIssue),
+		}
+	}
+}
+
+func (g *gracePeriod) hold(i issue.Issue) {
+	if _, ok := g.held[i.ID]; !ok {
+		seenAt := time.Now()
+		if g.fromCreated && !i.CreatedAt.IsZero() {
+			seenAt = i.CreatedAt
+		}
+		g.held[i.ID] = heldIssue{issue: i, seenAt: seenAt}
+	}
+}
+
+// releaseHeldIssues notifies about held issues whose grace period has passed
+// and that are still open. Failed deliveries stay held for the next poll.
+func (s *Service) releaseHeldIssues(ctx context.Context) error {
+	now := time.Now()
+	for id, held := range s.grace.held {
+		if now.Sub(held.seenAt) < s.grace.period {
+			continue
+		}
+
+		if err := s.limiter.Wait(ctx); err != nil {
+			return fmt.Errorf("rate limit error: %v", err)
+		}
+		current, err := s.grace.refresher.RefreshIssue(ctx, held.issue)
+		if errors.Is(err, repository.ErrIssueNotFound) {
+			s.trace(held.issue, "removed during the grace period, skipping")
+			delete(s.grace.held, id)
+			continue
+		}
+		if err != nil {
+			s.logger.Error("Error re-checking issue after grace period", "event", "grace_period", "issue_number", held.issue.Number, "error", err)
+			continue
+		}
+
+		if current.State != "open" {
+			s.trace(held.issue, "closed during the grace period, skipping")
+			s.logger.Info("Skipping issue closed during the grace period", "event", "grace_period", "issue_number", held.issue.Number)
+			delete(s.grace.held, id)
+			continue
+		}
+
+		s.trace(held.issue, "still open after the grace period, notifying")
+		if s.deliverNewIssue(ctx, *current) {
+			delete(s.grace.held, id)
+		} else {
+			s.lastFailed++
+		}
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"gitnotifier/internal/issue"
+	"gitnotifier/internal/repository"
+	"gitnotifier/internal/state"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+// stubRefresher implements IssueRefresher with the current state of each
+// issue by number. Issues without a state are reported as deleted.
+type stubRefresher map[int]string
+
+func (r stubRefresher) RefreshIssue(ctx context.Context, i issue.Issue) (*issue.Issue, error) {
+	state, ok := r[i.Number]
+	if !ok {
+		return nil, repository.ErrIssueNotFound
+	}
+	current := withState(i, state)
+	return &current, nil
+}
+
+// expireGracePeriod makes every held issue due for its re-check
+func expireGracePeriod(s *Service) {
+	for id, held := range s.grace.held {
+		held.seenAt = held.seenAt.Add(-s.grace.period)
+		s.grace.held[id] = held
+	}
+}
+
+func TestGracePeriodSkipsIssuesClosedDuringTheWindow(t *testing.T) {
+	n := &recordingNotifier{}
+	repo := &MockRepository{Latest: [][]issue.Issue{testIssues(1, 2, 3)}}
+	// #2 is closed and #3 deleted while they are held
+	refresher := stubRefresher{1: "open", 2: "closed"}
+	s := newTestService(repo, n, 100, WithGracePeriod(refresher, time.Hour))
+
+	if err := s.checkForNewIssues(context.Background()); err != nil {
+		t.Fatalf("checkForNewIssues: %v", err)
+	}
+	if got := n.urls(); len(got) != 0 {
+		t.Fatalf("notified %v before the grace period ended", got)
+	}
+
+	expireGracePeriod(s)
+	if err := s.checkForNewIssues(context.Background()); err != nil {
+		t.Fatalf("checkForNewIssues: %v", err)
+	}
+	if got, want := n.urls(), []string{issueURL(1)}; !slices.Equal(got, want) {
+		t.Errorf("notified %v, want only the issue still open %v", got, want)
+	}
+	if len(s.grace.held) != 0 {
+		t.Errorf("%d issues still held, want none", len(s.grace.held))
+	}
+
+	// Issues are not held or notified again on later polls
+	expireGracePeriod(s)
+	if err := s.checkForNewIssues(context.Background()); err != nil {
+		t.Fatalf("checkForNewIssues: %v", err)
+	}
+	if got := n.urls(); len(got) != 1 {
+		t.Errorf("notified %v after the issues were handled", got)
+	}
+}
+
+func TestGracePeriodKeepsCursorBelowHeldIssues(t *testing.T) {
+	store := state.NewStore(filepath.Join(t.TempDir(), "state.json"))
+	saved := func() int { return store.Get().Repos["owner/repo"].LastCheckID }
+	newService := func(n *recordingNotifier) *Service {
+		s := newTestService(&MockRepository{Latest: [][]issue.Issue{testIssues(1, 2)}}, n, 0,
+			WithPersistedCursor(store, "owner/repo"), WithGracePeriod(stubRefresher{1: "open", 2: "open"}, time.Hour))
+		s.lastCheckID = max(s.lastCheckID, 100)
+		return s
+	}
+
+	n := &recordingNotifier{}
+	s := newService(n)
+	if err := s.checkForNewIssues(context.Background()); err != nil {
+		t.Fatalf("checkForNewIssues: %v", err)
+	}
+	if s.lastCheckID != 100 || saved() != 100 {
+		t.Fatalf("cursor = %d, saved %d, want 100 while #1 and #2 are held", s.lastCheckID, saved())
+	}
+
+	// After a restart the held issues are found and held again rather than lost
+	n = &recordingNotifier{}
+	s = newService(n)
+	if err := s.checkForNewIssues(context.Background()); err != nil {
+		t.Fatalf("checkForNewIssues: %v", err)
+	}
+	if len(s.grace.held) != 2 {
+		t.Fatalf("%d issues held after the restart, want 2", len(s.grace.held))
+	}
+
+	// Only #1 can be delivered, so the cursor stops below #2
+	n.fail = failURLs(issueURL(2))
+	expireGracePeriod(s)
+	if err := s.checkForNewIssues(context.Background()); err != nil {
+		t.Fatalf("checkForNewIssues: %v", err)
+	}
+	if got, want := n.urls(), []string{issueURL(1)}; !slices.Equal(got, want) {
+		t.Errorf("notified %v, want %v", got, want)
+	}
+	if s.lastCheckID != 101 || saved() != 101 {
+		t.Errorf("cursor = %d, saved %d, want 101 while #2 is still held", s.lastCheckID, saved())
+	}
+
+	n.fail = nil
+	if err := s.checkForNewIssues(context.Background()); err != nil {
+		t.Fatalf("checkForNewIssues: %v", err)
+	}
+	if got, want := n.urls(), []string{issueURL(1), issueURL(2)}; !slices.Equal(got, want) {
+		t.Errorf("notified %v, want %v", got, want)
+	}
+	if s.lastCheckID != 102 || saved() != 102 {
+		t.Errorf("cursor = %d, saved %d, want 102 once every held issue was delivered", s.lastCheckID, saved())
+	}
+}
+
+func TestRunOnceHoldsIssuesFromCreation(t *testing.T) {
+	store := state.NewStore(filepath.Join(t.TempDir(), "state.json"))
+	fetched := testIssues(1, 2)
+	fetched[0].CreatedAt = time.Now() // #2 is still within the grace period
+	n := &recordingNotifier{}
+	s := newTestService(&MockRepository{Latest: [][]issue.Issue{fetched}}, n, 0,
+		WithPersistedCursor(store, "owner/repo"), WithGracePeriod(stubRefresher{1: "open", 2: "open"}, time.Hour))
+	s.lastCheckID = 100
+
+	if err := s.RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+	if got, want := n.urls(), []string{issueURL(1)}; !slices.Equal(got, want) {
+		t.Errorf("notified %v, want only the issue older than the grace period %v", got, want)
+	}
+	if got := store.Get().Repos["owner/repo"].LastCheckID; got != 101 {
+		t.Errorf("saved cursor %d, want 101 so a later run handles #2", got)
+	}
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"gitnotifier/internal/issue"
+)
+
+// LinkedPullRequestFetcher defines the interface for looking up pull requests that reference an issue
+type LinkedPullRequestFetcher interface {
+	FetchLinkedPullRequests(ctx context.Context, i issue.Issue) ([]issue.LinkedPullRequest, error)
+}
+
+// linkedPullRequests adds the status of linked pull requests to new-issue
+// notifications, within a budget of extra API calls per poll
+type linkedPullRequests struct {
+	fetcher    LinkedPullRequestFetcher
+	maxPerPoll int
+	used       int
+}
+
+// WithLinkedPullRequests mentions pull requests that reference a new issue in
+// its notification, looking up at most maxPerPoll issues per poll. Lookups
+// are skipped while the rate limit is low.
+func WithLinkedPullRequests(fetcher LinkedPullRequestFetcher, maxPerPoll int) Option {
+	return func(s *Service) {
+		if maxPerPoll <= 0 {
+			return
+		}
+		s.linkedPRs = &linkedPullRequests{fetcher: fetcher, maxPerPoll: maxPerPoll}
+	}
+}
+
+// withLinkedPullRequests returns issue with its linked pull requests filled in
+// when the lookup budget and rate limit allow. Failures only drop the enrichment.
+func (s *Service) withLinkedPullRequests(ctx context.Context, issue issue.Issue) issue.Issue {
+	if s.linkedPRs.used >= s.linkedPRs.maxPerPoll || s.rateLimitLow() {
+		s.trace(issue, "skipping linked pull request lookup")
+		return issue
+	}
+	s.linkedPRs.used++
+
+	linked, err := s.linkedPRs.fetcher.FetchLinkedPullRequests(ctx, issue)
+	if err != nil {
+		s.logger.Error("Error looking up linked pull requests", "event", "linked_prs", "issue_number", issue.Number, "error", err)
+		return issue
+	}
+	issue.LinkedPullRequests = linked
+	return issue
+}
+
+// rateLimitLow reports whether less than a tenth of the API quota is left
+func (s *Service) rateLimitLow() bool {
+	source, ok := s.repo.(RateLimitSource)
+	if !ok {
+		return false
+	}
+	limit, ok := source.RateLimit()
+	return ok && limit.Limit > 0 && limit.Remaining*10 < limit.Limit
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"gitnotifier/config"
+	"gitnotifier/internal/issue"
+	"gitnotifier/internal/repository"
+	"gitnotifier/internal/state"
+	"maps"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MilestoneFetcher defines the interface for fetching milestones and their issues
+type MilestoneFetcher interface {
+	FetchOpenMilestones(ctx context.Context) ([]repository.Milestone, error)
+	FetchMilestoneIssues(ctx context.Context, number int) ([]issue.Issue, error)
+}
+
+// milestoneWatch reminds about open issues in milestones that are due soon
+type milestoneWatch struct {
+	fetcher MilestoneFetcher
+	lead    time.Duration
+	titles  map[string]bool // Watched milestone titles, all when empty
+	store   *state.Store
+	key     string // Repository key in the persisted state, e.g. "owner/repo"
+}
+
+// WithMilestoneDeadlines notifies once per issue when an open issue's
+// milestone is due within lead. Milestones are scanned once a day; titles
+// limits the scan to those milestones. The last scan and the reminders sent
+// are persisted under key.
+func WithMilestoneDeadlines(fetcher MilestoneFetcher, lead time.Duration, titles []string, store *state.Store, key string) Option {
+	return func(s *Service) {
+		w := &milestoneWatch{
+			fetcher: fetcher,
+			lead:    lead,
+			titles:  make(map[string]bool, len(titles)),
+			store:   store,
+			key:     key,
+		}
+		for _, t := range titles {
+			w.titles[t] = true
+		}
+		s.milestones = w
+	}
+}
+
+func (s *Service) checkMilestoneDeadlines(ctx context.Context) error {
+	last := s.milestones.store.Get().Repos[s.milestones.key]
+	if time.Since(last.MilestoneScan) < config.MilestoneScanInterval {
+		return nil
+	}
+
+	if err := s.limiter.Wait(ctx); err != nil {
+		return fmt.Errorf("rate limit error: %v", err)
+	}
+	milestones, err := s.milestones.fetcher.FetchOpenMilestones(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	sent := maps.Clone(last.MilestoneReminders)
+	if sent == nil {
+		sent = make(map[string]bool)
+	}
+	if err := s.remindMilestones(ctx, milestones, now, sent); err != nil {
+		// Keep the reminders that went out so the next scan doesn't repeat them
+		if saveErr := s.saveMilestoneReminders(last.MilestoneScan, sent); saveErr != nil {
+			s.logger.Error("Error saving milestone reminders", "event", "milestone_deadline", "error", saveErr)
+		}
+		return err
+	}
+
+	// Reminders for milestones that were closed can't come up again
+	open := make(map[string]bool, len(milestones))
+	for _, m := range milestones {
+		open[strconv.Itoa(m.Number)] = true
+	}
+	for key := range sent {
+		if number, _, _ := strings.Cut(key, ":"); !open[number] {
+			delete(sent, key)
+		}
+	}
+	return s.saveMilestoneReminders(now, sent)
+}
+
+// remindMilestones notifies about the open issues of milestones due within
+// the lead time, adding each reminder sent to sent
+func (s *Service) remindMilestones(ctx context.Context, milestones []repository.Milestone, now time.Time, sent map[string]bool) error {
+	for _, m := range milestones {
+		if len(s.milestones.titles) > 0 && !s.milestones.titles[m.Title] {
+			continue
+		}
+		until := m.DueOn.Sub(now)
+		if until < 0 || until > s.milestones.lead {
+			continue
+		}
+
+		if err := s.limiter.Wait(ctx); err != nil {
+			return fmt.Errorf("rate limit error: %v", err)
+		}
+		issues, err := s.milestones.fetcher.FetchMilestoneIssues(ctx, m.Number)
+		if err != nil {
+			return err
+		}
+
+		for _, issue := range issues {
+			key := fmt.Sprintf("%d:%d", m.Number, issue.ID)
+			if sent[key] {
+				continue
+			}
+			if err := s.issueNotifier.NotifyMilestoneDeadline(issue, m.Title, until); err != nil {
+				s.logger.Error("Error sending milestone reminder", "event", "milestone_deadline", "issue_number", issue.Number, "error", err)
+				continue
+			}
+			s.logger.Info("Sent milestone reminder", "event", "milestone_deadline", "issue_number", issue.Number, "milestone", m.Title, "due", m.DueOn.Format(time.DateOnly))
+			sent[key] = true
+		}
+	}
+	return nil
+}
+
+func (s *Service) saveMilestoneReminders(scanned time.Time, sent map[string]bool) error {
+	return s.milestones.store.Update(func(st *state.State) {
+		if st.Repos == nil {
+			st.Repos = make(map[string]state.RepoState)
+		}
+		rs := st.Repos[s.milestones.key]
+		rs.MilestoneScan = scanned
+		rs.MilestoneReminders = sent
+		st.Repos[s.milestones.key] = rs
+	})
+}
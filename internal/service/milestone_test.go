@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"gitnotifier/internal/issue"
+	"gitnotifier/internal/repository"
+	"gitnotifier/internal/state"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// fakeMilestones is a MilestoneFetcher serving the same milestones and issues on every call
+type fakeMilestones struct {
+	milestones []repository.Milestone
+	issues     []issue.Issue
+}
+
+func (f *fakeMilestones) FetchOpenMilestones(ctx context.Context) ([]repository.Milestone, error) {
+	return f.milestones, nil
+}
+
+func (f *fakeMilestones) FetchMilestoneIssues(ctx context.Context, number int) ([]issue.Issue, error) {
+	return f.issues, nil
+}
+
+func TestMilestoneRemindersSurviveRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	due := time.Now().Add(48 * time.Hour)
+	fetcher := &fakeMilestones{
+		milestones: []repository.Milestone{{Number: 3, Title: "v1.0", DueOn: &due}},
+		issues:     []issue.Issue{testIssue(1)},
+	}
+
+	// restart creates a service as if the process had restarted, a day after the previous scan
+	restart := func() (*Service, *recordingNotifier, *state.Store) {
+		store := state.NewStore(path)
+		if err := store.Load(); err != nil {
+			t.Fatalf("Load: %v", err)
+		}
+		if err := store.Update(func(st *state.State) {
+			if rs, ok := st.Repos["owner/repo"]; ok {
+				rs.MilestoneScan = rs.MilestoneScan.Add(-25 * time.Hour)
+				st.Repos["owner/repo"] = rs
+			}
+		}); err != nil {
+			t.Fatal(err)
+		}
+		n := &recordingNotifier{}
+		s := newTestService(&MockRepository{}, n, 100, WithMilestoneDeadlines(fetcher, 72*time.Hour, nil, store, "owner/repo"))
+		return s, n, store
+	}
+
+	s, n, _ := restart()
+	if err := s.checkMilestoneDeadlines(context.Background()); err != nil {
+		t.Fatalf("checkMilestoneDeadlines: %v", err)
+	}
+	if got := len(n.urls()); got != 1 {
+		t.Fatalf("sent %d reminders, want 1", got)
+	}
+	if err := s.checkMilestoneDeadlines(context.Background()); err != nil {
+		t.Fatalf("checkMilestoneDeadlines: %v", err)
+	}
+	if got := len(n.urls()); got != 1 {
+		t.Errorf("sent %d reminders, want no scan within a day of the last", got)
+	}
+
+	s, n, _ = restart()
+	if err := s.checkMilestoneDeadlines(context.Background()); err != nil {
+		t.Fatalf("checkMilestoneDeadlines: %v", err)
+	}
+	if got := len(n.urls()); got != 0 {
+		t.Errorf("sent %d reminders after a restart, want the earlier one remembered", got)
+	}
+
+	// Closing the milestone drops its reminders
+	fetcher.milestones = nil
+	s, _, store := restart()
+	if err := s.checkMilestoneDeadlines(context.Background()); err != nil {
+		t.Fatalf("checkMilestoneDeadlines: %v", err)
+	}
+	if got := store.Get().Repos["owner/repo"].MilestoneReminders; len(got) != 0 {
+		t.Errorf("reminders = %v after the milestone closed, want none", got)
+	}
+}
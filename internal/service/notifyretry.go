@@ -0,0 +1,36 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"gitnotifier/internal/ctxutil"
+	"gitnotifier/internal/notifier"
+)
+
+// WithNotifyRetries retries a failed new-issue notification up to n more
+// times, config.NotifyRetryDelay apart, before giving up until the next poll
+func WithNotifyRetries(n int) Option {
+	return func(s *Service) {
+		s.notifyRetries = max(n, 0)
+	}
+}
+
+// notifyWithRetry calls send until it succeeds, the retries are used up or ctx
+// is cancelled. A notification dropped while paused or already sent is not
+// retried, and after a partial failure only the channels that failed are retried.
+func (s *Service) notifyWithRetry(ctx context.Context, send func() error) error {
+	for attempt := 0; ; attempt++ {
+		err := send()
+		var partial *notifier.PartialError
+		if errors.As(err, &partial) {
+			send = partial.Retry
+		}
+		if err == nil || errors.Is(err, notifier.ErrPaused) || errors.Is(err, notifier.ErrAlreadySent) || attempt >= s.notifyRetries {
+			return err
+		}
+		s.logger.Warn("Error sending notification, retrying", "event", "notification", "attempt", attempt+1, "error", err)
+		if err := ctxutil.Sleep(ctx, s.retryDelay); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"fmt"
+	"gitnotifier/internal/issue"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// NumberSet is a set of issue numbers stored as sorted, non-overlapping
+// inclusive ranges, so large imported batches stay cheap to look up
+type NumberSet struct {
+	ranges [][2]int
+}
+
+// ParseNumberSet parses a comma-separated list of numbers and ranges such as
+// "5,10,100-150". Overlapping and adjacent ranges are merged.
+func ParseNumberSet(spec string) (*NumberSet, error) {
+	var ranges [][2]int
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		lo, hi, isRange := strings.Cut(part, "-")
+		start, err := strconv.Atoi(strings.TrimSpace(lo))
+		if err != nil || start <= 0 {
+			return nil, fmt.Errorf("invalid issue number %q", part)
+		}
+		end := start
+		if isRange {
+			if end, err = strconv.Atoi(strings.TrimSpace(hi)); err != nil || end < start {
+				return nil, fmt.Errorf("invalid issue range %q. Expected 'first-last' with first <= last", part)
+			}
+		}
+		ranges = append(ranges, [2]int{start, end})
+	}
+	if len(ranges) == 0 {
+		return nil, fmt.Errorf("no issue numbers in %q", spec)
+	}
+
+	slices.SortFunc(ranges, func(a, b [2]int) int { return a[0] - b[0] })
+	merged := ranges[:1]
+	for _, r := range ranges[1:] {
+		last := &merged[len(merged)-1]
+		if r[0] <= last[1]+1 {
+			last[1] = max(last[1], r[1])
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return &NumberSet{ranges: merged}, nil
+}
+
+// Contains reports whether n is in the set
+func (ns *NumberSet) Contains(n int) bool {
+	_, found := slices.BinarySearchFunc(ns.ranges, n, func(r [2]int, n int) int {
+		switch {
+		case r[1] < n:
+			return -1
+		case r[0] > n:
+			return 1
+		default:
+			return 0
+		}
+	})
+	return found
+}
+
+// NumberFilter rejects issues in mute and, when watch is set, issues outside
+// it. Either may be nil.
+func NumberFilter(mute, watch *NumberSet) Filter {
+	return func(issue issue.Issue) (bool, string) {
+		if mute != nil && mute.Contains(issue.Number) {
+			return false, "issue number is in MUTE_ISSUES"
+		}
+		if watch != nil && !watch.Contains(issue.Number) {
+			return false, "issue number is not in WATCH_ISSUES"
+		}
+		return true, ""
+	}
+}
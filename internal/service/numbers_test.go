@@ -0,0 +1,79 @@
+package service
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseNumberSet(t *testing.T) {
+	tests := []struct {
+		name string
+		spec string
+		want [][2]int
+	}{
+		{"single number", "5", [][2]int{{5, 5}}},
+		{"list and range", "5,10,100-150", [][2]int{{5, 5}, {10, 10}, {100, 150}}},
+		{"spaces and empty entries", " 10 , ,100 - 150,", [][2]int{{10, 10}, {100, 150}}},
+		{"unsorted", "100-150,5", [][2]int{{5, 5}, {100, 150}}},
+		{"overlapping ranges", "100-150,120-200,140", [][2]int{{100, 200}}},
+		{"range inside another", "1-100,20-30", [][2]int{{1, 100}}},
+		{"adjacent ranges", "1-5,6-10,11", [][2]int{{1, 11}}},
+		{"single number range", "7-7", [][2]int{{7, 7}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ns, err := ParseNumberSet(tt.spec)
+			if err != nil {
+				t.Fatalf("ParseNumberSet(%q): %v", tt.spec, err)
+			}
+			if !slices.Equal(ns.ranges, tt.want) {
+				t.Errorf("ParseNumberSet(%q) = %v, want %v", tt.spec, ns.ranges, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseNumberSetInvalid(t *testing.T) {
+	for _, spec := range []string{"", " , ", "abc", "0", "-5", "10-", "150-100", "5-x", "1.5", "3,four"} {
+		if ns, err := ParseNumberSet(spec); err == nil {
+			t.Errorf("ParseNumberSet(%q) = %v, want an error", spec, ns.ranges)
+		}
+	}
+}
+
+func TestNumberSetContains(t *testing.T) {
+	ns, err := ParseNumberSet("5,10,100-150,120-200")
+	if err != nil {
+		t.Fatalf("ParseNumberSet: %v", err)
+	}
+	for n, want := range map[int]bool{1: false, 5: true, 6: false, 10: true, 99: false, 100: true, 150: true, 175: true, 200: true, 201: false} {
+		if got := ns.Contains(n); got != want {
+			t.Errorf("Contains(%d) = %v, want %v", n, got, want)
+		}
+	}
+}
+
+func TestNumberFilter(t *testing.T) {
+	mute, _ := ParseNumberSet("3")
+	watch, _ := ParseNumberSet("1-3")
+	tests := []struct {
+		name        string
+		mute, watch *NumberSet
+		number      int
+		want        bool
+	}{
+		{"muted", mute, nil, 3, false},
+		{"not muted", mute, nil, 4, true},
+		{"watched", nil, watch, 2, true},
+		{"not watched", nil, watch, 4, false},
+		{"muted wins over watched", mute, watch, 3, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, reason := NumberFilter(tt.mute, tt.watch)(testIssue(tt.number))
+			if ok != tt.want {
+				t.Errorf("NumberFilter(#%d) = %v (%s), want %v", tt.number, ok, reason, tt.want)
+			}
+		})
+	}
+}
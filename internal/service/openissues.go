@@ -0,0 +1,34 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"gitnotifier/internal/notifier"
+)
+
+// WithOpenIssues records notified new issues in open and forgets them once
+// they are closed, transferred or deleted. Closed issues are found among the
+// recently updated ones, which costs a request every poll.
+func WithOpenIssues(open *notifier.OpenIssues) Option {
+	return func(s *Service) {
+		s.openIssues = open
+	}
+}
+
+// checkClosedIssues forgets recently updated issues that are no longer open
+func (s *Service) checkClosedIssues(ctx context.Context) error {
+	if err := s.limiter.Wait(ctx); err != nil {
+		return fmt.Errorf("rate limit error: %v", err)
+	}
+
+	issues, err := s.repo.FetchRecentlyUpdatedIssues(ctx)
+	if err != nil {
+		return err
+	}
+	for _, i := range issues {
+		if i.State != "open" {
+			s.openIssues.Closed(i.HTMLURL)
+		}
+	}
+	return nil
+}
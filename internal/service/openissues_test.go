@@ -0,0 +1,31 @@
+package service
+
+import (
+	"context"
+	"gitnotifier/internal/issue"
+	"gitnotifier/internal/notifier"
+	"testing"
+)
+
+func TestOpenIssuesFollowsNotifiedIssues(t *testing.T) {
+	open := notifier.NewOpenIssues()
+	repo := &MockRepository{
+		Latest:  [][]issue.Issue{testIssues(1, 2, 3)},
+		Updated: [][]issue.Issue{nil, {withState(testIssue(2), "closed"), testIssue(3)}},
+	}
+	s := newTestService(repo, &recordingNotifier{}, 100, WithOpenIssues(open))
+
+	if err := s.checkForNewIssues(context.Background()); err != nil {
+		t.Fatalf("checkForNewIssues: %v", err)
+	}
+	if got := open.Count(); got != 3 {
+		t.Errorf("%d open new issues after notifying three, want 3", got)
+	}
+
+	if err := s.checkForNewIssues(context.Background()); err != nil {
+		t.Fatalf("checkForNewIssues: %v", err)
+	}
+	if got := open.Count(); got != 2 {
+		t.Errorf("%d open new issues after #2 was closed, want 2", got)
+	}
+}
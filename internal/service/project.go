@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"gitnotifier/internal/repository"
+	"strings"
+	"time"
+)
+
+// CurrentIteration is the field value that matches whichever iteration is in progress
+const CurrentIteration = "@current"
+
+// ProjectItemFetcher defines the interface for fetching project items
+type ProjectItemFetcher interface {
+	FetchItems(ctx context.Context) ([]repository.ProjectItem, error)
+}
+
+// projectWatch notifies when project items start matching a field value
+type projectWatch struct {
+	fetcher  ProjectItemFetcher
+	field    string
+	value    string
+	seen     map[string]bool // Item IDs that already matched
+	baseline bool            // Whether the first fetch has been recorded
+}
+
+// WithProjectWatch notifies about project items whose field equals value.
+// Items matching on the first poll are recorded without notifying. The value
+// CurrentIteration matches items in the iteration that is in progress.
+func WithProjectWatch(fetcher ProjectItemFetcher, field, value string) Option {
+	return func(s *Service) {
+		s.project = &projectWatch{
+			fetcher: fetcher,
+			field:   field,
+			value:   value,
+			seen:    make(map[string]bool),
+		}
+	}
+}
+
+func (p *projectWatch) matches(item repository.ProjectItem, now time.Time) bool {
+	if p.value == CurrentIteration {
+		if item.IterationStart.IsZero() {
+			return false
+		}
+		return !now.Before(item.IterationStart) && now.Before(item.IterationStart.Add(item.IterationDuration))
+	}
+	return strings.EqualFold(item.FieldValue, p.value)
+}
+
+func (s *Service) checkProjectItems(ctx context.Context) error {
+	if err := s.limiter.Wait(ctx); err != nil {
+		return fmt.Errorf("rate limit error: %v", err)
+	}
+
+	items, err := s.project.fetcher.FetchItems(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, item := range items {
+		if !s.project.matches(item, now) || s.project.seen[item.ID] {
+			continue
+		}
+
+		if s.project.baseline {
+			if err := s.issueNotifier.NotifyProjectMatch(item, s.project.field, s.project.value); err != nil {
+				s.logger.Error("Error sending notification for project item", "event", "project_item", "item", item.ID, "error", err)
+				continue
+			}
+			s.logger.Info("Sent notification for project item", "event", "project_item", "title", item.Title, "field", s.project.field, "value", s.project.value)
+		}
+		s.project.seen[item.ID] = true
+	}
+
+	if !s.project.baseline {
+		s.logger.Info("Tracking project items", "event", "project_item", "field", s.project.field, "value", s.project.value, "matching", len(s.project.seen))
+		s.project.baseline = true
+	}
+	return nil
+}
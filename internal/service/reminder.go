@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gitnotifier/internal/issue"
+	"gitnotifier/internal/repository"
+	"time"
+)
+
+// IssueFetcher defines the interface for looking up a single issue
+type IssueFetcher interface {
+	FetchIssue(ctx context.Context, number int) (*issue.Issue, error)
+}
+
+// reminders re-notifies about issues that stay open too long after being announced
+type reminders struct {
+	fetcher IssueFetcher
+	after   time.Duration
+	tracked map[int]*trackedIssue // Keyed by issue number
+}
+
+type trackedIssue struct {
+	firstNotified time.Time
+	lastNotified  time.Time
+}
+
+// WithReminders re-sends a notification for issues still open after the given
+// duration, at most once per duration for each issue
+func WithReminders(fetcher IssueFetcher, after time.Duration) Option {
+	return func(s *Service) {
+		if after <= 0 {
+			return
+		}
+		s.reminders = &reminders{
+			fetcher: fetcher,
+			after:   after,
+			tracked: make(map[int]*trackedIssue),
+		}
+	}
+}
+
+// track starts the reminder clock for an issue that was just announced
+func (r *reminders) track(i issue.Issue) {
+	now := time.Now()
+	r.tracked[i.Number] = &trackedIssue{firstNotified: now, lastNotified: now}
+}
+
+func (s *Service) sendReminders(ctx context.Context) error {
+	now := time.Now()
+	for number, tracked := range s.reminders.tracked {
+		if now.Sub(tracked.lastNotified) < s.reminders.after {
+			continue
+		}
+
+		// Only the issues that are due are looked up, to keep API usage low
+		if err := s.limiter.Wait(ctx); err != nil {
+			return fmt.Errorf("rate limit error: %v", err)
+		}
+		current, err := s.reminders.fetcher.FetchIssue(ctx, number)
+		if errors.Is(err, repository.ErrIssueNotFound) {
+			delete(s.reminders.tracked, number)
+			continue
+		}
+		if err != nil {
+			s.logger.Error("Error checking issue for reminder", "event", "reminder", "issue_number", number, "error", err)
+			continue
+		}
+
+		if current.State != "open" {
+			delete(s.reminders.tracked, number)
+			continue
+		}
+
+		openFor := now.Sub(tracked.firstNotified)
+		if err := s.issueNotifier.NotifyReminder(*current, openFor); err != nil {
+			s.logger.Error("Error sending reminder", "event", "reminder", "issue_number", number, "error", err)
+			continue
+		}
+		s.logger.Info("Sent reminder", "event", "reminder", "issue_number", number, "open_for", openFor.Round(time.Minute).String())
+		tracked.lastNotified = now
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"gitnotifier/internal/repository"
+	"gitnotifier/internal/state"
+)
+
+// RepoInfoFetcher defines the interface for fetching repository metadata
+type RepoInfoFetcher interface {
+	FetchRepoInfo(ctx context.Context) (*repository.RepoInfo, error)
+}
+
+// starWatch tracks stargazer milestones for a repository
+type starWatch struct {
+	fetcher RepoInfoFetcher
+	step    int
+	store   *state.Store
+	key     string // Repository key in the persisted state, e.g. "owner/repo"
+}
+
+// WithStarMilestones notifies each time the repository's star count crosses a
+// multiple of step. The last announced milestone is persisted under key.
+func WithStarMilestones(fetcher RepoInfoFetcher, step int, store *state.Store, key string) Option {
+	return func(s *Service) {
+		if step <= 0 {
+			return
+		}
+		s.stars = &starWatch{fetcher: fetcher, step: step, store: store, key: key}
+	}
+}
+
+func (s *Service) checkStarMilestones(ctx context.Context) error {
+	if err := s.limiter.Wait(ctx); err != nil {
+		return fmt.Errorf("rate limit error: %v", err)
+	}
+
+	info, err := s.stars.fetcher.FetchRepoInfo(ctx)
+	if err != nil {
+		return err
+	}
+
+	milestone := info.StargazersCount / s.stars.step * s.stars.step
+	last, known := s.stars.store.Get().Repos[s.stars.key]
+
+	// Record the current milestone silently the first time so existing stars aren't announced
+	if !known || last.StarMilestone == 0 {
+		s.logger.Info("Tracking star milestones", "event", "stars", "key", s.stars.key, "stars", info.StargazersCount)
+		return s.saveStarMilestone(milestone)
+	}
+
+	// Drops in stars are ignored so hovering around a threshold doesn't re-notify
+	if milestone <= last.StarMilestone {
+		return nil
+	}
+
+	if err := s.issueNotifier.NotifyStarMilestone(info.FullName, milestone, info.StargazersCount, info.HTMLURL); err != nil {
+		return fmt.Errorf("error sending star milestone notification: %v", err)
+	}
+	s.logger.Info("Sent star milestone notification", "event", "stars", "repository", info.FullName, "milestone", milestone)
+
+	return s.saveStarMilestone(milestone)
+}
+
+func (s *Service) saveStarMilestone(milestone int) error {
+	return s.stars.store.Update(func(st *state.State) {
+		if st.Repos == nil {
+			st.Repos = make(map[string]state.RepoState)
+		}
+		rs := st.Repos[s.stars.key]
+		rs.StarMilestone = milestone
+		st.Repos[s.stars.key] = rs
+	})
+}
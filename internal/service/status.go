@@ -0,0 +1,87 @@
+package service
+
+import (
+	"gitnotifier/internal/issue"
+	"sync"
+	"time"
+)
+
+// Status is a snapshot of a service's progress
+type Status struct {
+	LastPoll    time.Time
+	LastError   string // Empty when the last poll succeeded
+	LastCheckID int
+}
+
+// status is the part of the service state read from other goroutines
+type status struct {
+	mu      sync.Mutex
+	current Status
+}
+
+// Status returns the outcome of the most recent poll
+func (s *Service) Status() Status {
+	s.status.mu.Lock()
+	defer s.status.mu.Unlock()
+	return s.status.current
+}
+
+func (s *Service) recordStatus(err error) {
+	s.status.mu.Lock()
+	defer s.status.mu.Unlock()
+
+	s.status.current.LastPoll = time.Now()
+	s.status.current.LastError = ""
+	if err != nil {
+		s.status.current.LastError = err.Error()
+	}
+	s.status.current.LastCheckID = s.lastCheckID
+}
+
+// NotifiedIssue is an issue that a service sent a new-issue notification for
+type NotifiedIssue struct {
+	Issue      issue.Issue
+	Source     string // Service name, empty for an unnamed service
+	NotifiedAt time.Time
+}
+
+// RecentIssues keeps the most recently notified issues across services
+type RecentIssues struct {
+	size   int
+	mu     sync.Mutex
+	issues []NotifiedIssue
+}
+
+// NewRecentIssues creates a RecentIssues holding up to size issues
+func NewRecentIssues(size int) *RecentIssues {
+	return &RecentIssues{size: size}
+}
+
+// WithRecentIssues records each notified new issue in r
+func WithRecentIssues(r *RecentIssues) Option {
+	return func(s *Service) {
+		s.recent = r
+	}
+}
+
+func (r *RecentIssues) add(i issue.Issue, source string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.issues = append(r.issues, NotifiedIssue{Issue: i, Source: source, NotifiedAt: time.Now()})
+	if len(r.issues) > r.size {
+		r.issues = r.issues[len(r.issues)-r.size:]
+	}
+}
+
+// List returns the recorded issues, newest first
+func (r *RecentIssues) List() []NotifiedIssue {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	list := make([]NotifiedIssue, 0, len(r.issues))
+	for i := len(r.issues) - 1; i >= 0; i-- {
+		list = append(list, r.issues[i])
+	}
+	return list
+}
@@ -1,12 +1,22 @@
 package service
 
 import (
+	"cmp"
 	"context"
+	"errors"
 	"fmt"
+	"gitnotifier/config"
+	"gitnotifier/internal/ctxutil"
+	"gitnotifier/internal/issue"
+	"gitnotifier/internal/metrics"
 	"gitnotifier/internal/notifier"
 	"gitnotifier/internal/repository"
-	"log"
+	"log/slog"
+	"runtime/debug"
+	"slices"
 	"sync"
+	"sync/atomic"
+	"text/template"
 	"time"
 
 	"golang.org/x/time/rate"
@@ -20,20 +30,187 @@ type Service struct {
 	pollInterval   time.Duration
 	limiter        *rate.Limiter
 	shutdownChan   chan struct{}
-	wg             sync.WaitGroup
+	wg             sync.WaitGroup // Tracks running polls so shutdown can wait for them
+	delivered      atomic.Int64   // Notifications sent, for reporting what Stop drained
 	lastNotifyTime time.Time
 	notifyMutex    sync.Mutex
+	notifyDelay    time.Duration // Minimum gap between new-issue notifications
+	notifyRetries  int           // Extra attempts for a failed new-issue notification
+	retryDelay     time.Duration // Wait between those attempts
+	notifiedAhead  map[int]bool  // Handled issues above lastCheckID, held back by a failed notification
+	batchThreshold int           // New issues per poll above which one summary is sent, 0 to disable
+	transitions    map[Transition]bool
+	knownStates    map[int]knownState
+	name           string
+	traceIssue     int
+	stars          *starWatch
+	reminders      *reminders
+	filters        []Filter
+	dedup          *Dedup
+	titleDedup     *TitleDedup
+	project        *projectWatch
+	workflows      *workflowWatch
+	advisories     *advisoryWatch
+	vanished       *vanishedWatch
+	grace          *gracePeriod
+	cursor         *cursor
+	adaptive       *adaptiveInterval
+	linkedPRs      *linkedPullRequests
+	milestones     *milestoneWatch
+	comments       *commentWatch
+	recent         *RecentIssues
+	openIssues     *notifier.OpenIssues
+	status         status
+	oldestFirst    bool
+	notifySince    time.Time // Issues created earlier are only marked as seen on the initial check
+	metrics        *metrics.Metrics
+	lastFetched    int // Number of issues returned by the most recent poll
+	lastFailed     int // New-issue notifications that failed in the most recent poll
+	panics         int // Consecutive polls that ended in a panic
+	onInitialCheck func(fetched int, err error)
+	logger         *slog.Logger
+}
+
+// SinceFetcher is implemented by repositories that can page back to the last
+// seen issue, so bursts larger than one poll's worth of issues are not skipped
+type SinceFetcher interface {
+	FetchIssuesSince(ctx context.Context, lastID int) ([]issue.Issue, error)
+}
+
+// Option configures optional Service behavior
+type Option func(*Service)
+
+// WithTransitions enables state-change notifications for the given transitions
+func WithTransitions(transitions []Transition) Option {
+	return func(s *Service) {
+		if len(transitions) == 0 {
+			return
+		}
+		s.transitions = make(map[Transition]bool, len(transitions))
+		for _, t := range transitions {
+			s.transitions[t] = true
+		}
+		s.knownStates = make(map[int]knownState)
+	}
+}
+
+// WithName names the service, tagging its logs and notifications
+func WithName(name string) Option {
+	return func(s *Service) {
+		s.name = name
+		s.issueNotifier.Tag = name
+	}
+}
+
+// WithLogger sets the logger, typically carrying the watched repo or query as
+// an attribute. It must come before options that log, such as WithPersistedCursor.
+func WithLogger(logger *slog.Logger) Option {
+	return func(s *Service) {
+		s.logger = logger
+	}
+}
+
+// WithRepoTags tags notifications with each issue's owner/repo, for views
+// such as an org watch that span several repositories
+func WithRepoTags() Option {
+	return func(s *Service) {
+		s.issueNotifier.TagWithRepo = true
+	}
+}
+
+// WithRenderMarkdown renders markdown in issue text as plain text in notifications
+func WithRenderMarkdown() Option {
+	return func(s *Service) {
+		s.issueNotifier.RenderMarkdown = true
+	}
+}
+
+// WithFormat selects the layout of new-issue notifications
+func WithFormat(format notifier.Format) Option {
+	return func(s *Service) {
+		s.issueNotifier.Format = format
+	}
+}
+
+// WithIssueTemplate renders new-issue notification messages with tmpl
+func WithIssueTemplate(tmpl *template.Template) Option {
+	return func(s *Service) {
+		s.issueNotifier.MessageTemplate = tmpl
+	}
+}
+
+// WithNotifyDelay sets the minimum gap between new-issue notifications,
+// config.NotifyDelay by default. Zero disables the spacing.
+func WithNotifyDelay(d time.Duration) Option {
+	return func(s *Service) {
+		s.notifyDelay = max(d, 0)
+	}
+}
+
+// WithNotifySince marks issues created before since as seen without notifying
+// when there is no last seen issue yet, so a first run on a mature repository
+// doesn't notify about its history
+func WithNotifySince(since time.Time) Option {
+	return func(s *Service) {
+		s.notifySince = since
+	}
+}
+
+// WithOldestFirst notifies about new issues in chronological order rather than
+// newest first. The last checked ID still advances to the highest ID seen.
+func WithOldestFirst() Option {
+	return func(s *Service) {
+		s.oldestFirst = true
+	}
+}
+
+// WithLimiter shares a rate limiter between services so that together they
+// stay within GitHub's limits
+func WithLimiter(limiter *rate.Limiter) Option {
+	return func(s *Service) {
+		s.limiter = limiter
+	}
+}
+
+// WithTraceIssue logs every decision the service makes about issue number n
+func WithTraceIssue(n int) Option {
+	return func(s *Service) {
+		s.traceIssue = n
+	}
+}
+
+// WithInitialCheckHook calls fn once the first poll completes, with the number
+// of issues it fetched and its error, if any
+func WithInitialCheckHook(fn func(fetched int, err error)) Option {
+	return func(s *Service) {
+		s.onInitialCheck = fn
+	}
+}
+
+// WithMetrics records poll counts in m
+func WithMetrics(m *metrics.Metrics) Option {
+	return func(s *Service) {
+		s.metrics = m
+	}
 }
 
 // NewService creates a new notification service
-func NewService(repo repository.IssueRepository, n notifier.Notifier, pollInterval time.Duration) *Service {
-	return &Service{
+func NewService(repo repository.IssueRepository, n notifier.Notifier, pollInterval time.Duration, opts ...Option) *Service {
+	s := &Service{
 		repo:          repo,
 		issueNotifier: notifier.NewIssueNotifier(n),
 		pollInterval:  pollInterval,
 		limiter:       rate.NewLimiter(rate.Every(time.Minute), 30),
 		shutdownChan:  make(chan struct{}),
+		notifyDelay:   config.NotifyDelay,
+		notifyRetries: config.DefaultNotifyRetries,
+		retryDelay:    config.NotifyRetryDelay,
+		logger:        slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
 }
 
 func (s *Service) checkForNewIssues(ctx context.Context) error {
@@ -42,58 +219,498 @@ func (s *Service) checkForNewIssues(ctx context.Context) error {
 		return fmt.Errorf("rate limit error: %v", err)
 	}
 
-	issues, err := s.repo.FetchLatestIssues(ctx)
+	var issues []issue.Issue
+	var err error
+	if f, ok := s.repo.(SinceFetcher); ok && s.lastCheckID > 0 {
+		issues, err = f.FetchIssuesSince(ctx, s.lastCheckID)
+	} else {
+		issues, err = s.repo.FetchLatestIssues(ctx)
+	}
 	if err != nil {
 		return err
 	}
+	s.lastFetched = len(issues)
+	s.lastFailed = 0
+	if s.linkedPRs != nil {
+		s.linkedPRs.used = 0
+	}
+
+	s.traceMissing(issues, "new issue check")
+	if s.oldestFirst {
+		// Sort a copy so the fetched order is preserved for the other checks
+		issues = slices.Clone(issues)
+		slices.SortStableFunc(issues, func(a, b issue.Issue) int {
+			if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+				return c
+			}
+			return cmp.Compare(a.ID, b.ID)
+		})
+	}
 
+	var pending []issue.Issue
+	seen := s.lastCheckID
+	initial := seen == 0 && !s.notifySince.IsZero()
+	seeded := 0
 	for _, issue := range issues {
-		if issue.ID > s.lastCheckID {
-			if err := s.issueNotifier.NotifyNewIssue(issue); err != nil {
-				log.Printf("Error sending notification for issue #%d: %v", issue.Number, err)
-				continue
+		// Compared with the ID at the start of the poll since lastCheckID moves during the loop
+		if issue.ID <= seen {
+			s.trace(issue, "already seen (id %d <= last checked id %d), skipping", issue.ID, seen)
+			continue
+		}
+
+		if s.notifiedAhead[issue.ID] {
+			s.trace(issue, "already handled while an earlier notification was being retried, skipping")
+			s.lastCheckID = max(s.lastCheckID, issue.ID)
+			continue
+		}
+
+		if initial && issue.CreatedAt.Before(s.notifySince) {
+			s.trace(issue, "created %s, before NOTIFY_SINCE %s, marking as seen", issue.CreatedAt.Format(time.RFC3339), s.notifySince.Format(time.RFC3339))
+			s.lastCheckID = max(s.lastCheckID, issue.ID)
+			seeded++
+			continue
+		}
+
+		if ok, reason := s.passesFilters(issue); !ok {
+			s.trace(issue, "filtered out: %s", reason)
+			s.lastCheckID = max(s.lastCheckID, issue.ID)
+			continue
+		}
+
+		if s.grace != nil {
+			s.trace(issue, "new (id %d > last checked id %d), holding for the %v grace period", issue.ID, seen, s.grace.period)
+			s.grace.hold(issue)
+			s.lastCheckID = max(s.lastCheckID, issue.ID)
+			continue
+		}
+
+		s.trace(issue, "new (id %d > last checked id %d), notifying", issue.ID, seen)
+		pending = append(pending, issue)
+	}
+
+	if seeded > 0 {
+		s.logger.Info("Marked issues created before NOTIFY_SINCE as seen", "event", "new_issue", "count", seeded, "since", s.notifySince.Format(time.RFC3339))
+	}
+
+	// Notifications in progress when ctx is cancelled get up to
+	// config.ShutdownTimeout to finish, so shutdown doesn't abandon them
+	deliverCtx, stop := ctxutil.Linger(ctx, config.ShutdownTimeout)
+	defer stop()
+
+	var failed []int
+	if s.batchThreshold > 0 && len(pending) > s.batchThreshold {
+		if s.deliverBatch(deliverCtx, pending) {
+			for _, issue := range pending {
+				s.lastCheckID = max(s.lastCheckID, issue.ID)
+			}
+		} else {
+			for _, issue := range pending {
+				failed = append(failed, issue.ID)
+			}
+		}
+	} else {
+		for _, issue := range pending {
+			if s.deliverNewIssue(deliverCtx, issue) {
+				s.lastCheckID = max(s.lastCheckID, issue.ID)
+			} else {
+				failed = append(failed, issue.ID)
 			}
-			log.Printf("Sent notification for new issue #%d: %s", issue.Number, issue.Title)
+		}
+	}
+	if len(failed) > 0 {
+		s.logger.Warn("Notifications failed, they will be retried on the next poll", "event", "new_issue", "count", len(failed))
+	}
+	s.lastFailed += len(failed)
+
+	// Held issues stay pending like failed ones, so the cursor only moves past
+	// them once they are delivered or dropped
+	unsettled := failed
+	var releaseErr error
+	if s.grace != nil {
+		releaseErr = s.releaseHeldIssues(ctx)
+		for id := range s.grace.held {
+			unsettled = append(unsettled, id)
+		}
+	}
+	s.holdBackCursor(issues, seen, unsettled)
+
+	if s.cursor != nil {
+		s.saveCursor()
+	}
+	if releaseErr != nil {
+		return releaseErr
+	}
 
-			if issue.ID > s.lastCheckID {
-				s.lastCheckID = issue.ID
+	// The optional watches don't depend on each other, so one failing, e.g.
+	// for lack of token permissions, is logged without skipping the others
+	// or failing the poll
+	watches := []struct {
+		name    string
+		enabled bool
+		check   func(context.Context) error
+	}{
+		{"vanished issues", s.vanished != nil, func(ctx context.Context) error { return s.checkVanished(ctx, issues) }},
+		{"state changes", s.transitions != nil, s.checkForStateChanges},
+		{"closed issues", s.openIssues != nil, s.checkClosedIssues},
+		{"star milestones", s.stars != nil, s.checkStarMilestones},
+		{"project items", s.project != nil, s.checkProjectItems},
+		{"workflow runs", s.workflows != nil, s.checkWorkflowRuns},
+		{"security advisories", s.advisories != nil, s.checkAdvisories},
+		{"comments", s.comments != nil, s.checkComments},
+		{"milestone deadlines", s.milestones != nil, s.checkMilestoneDeadlines},
+		{"reminders", s.reminders != nil, s.sendReminders},
+	}
+	for _, w := range watches {
+		if !w.enabled {
+			continue
+		}
+		if err := w.check(ctx); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
 			}
+			s.logger.Error("Error running optional watch", "event", "watch", "watch", w.name, "error", err)
 		}
 	}
 
 	return nil
 }
 
-// Start begins the notification service
+// holdBackCursor keeps lastCheckID below the oldest unsettled issue, whose
+// notification failed or is held, so the next poll handles it again. Issues
+// above that point which were already handled are remembered so they aren't
+// notified twice.
+func (s *Service) holdBackCursor(issues []issue.Issue, seen int, unsettled []int) {
+	if len(unsettled) > 0 {
+		cursor := max(seen, slices.Min(unsettled)-1)
+		if s.notifiedAhead == nil {
+			s.notifiedAhead = make(map[int]bool)
+		}
+		for _, issue := range issues {
+			if issue.ID > cursor && !slices.Contains(unsettled, issue.ID) {
+				s.notifiedAhead[issue.ID] = true
+			}
+		}
+		s.lastCheckID = min(s.lastCheckID, cursor)
+	}
+
+	for id := range s.notifiedAhead {
+		if id <= s.lastCheckID {
+			delete(s.notifiedAhead, id)
+		}
+	}
+}
+
+// deliverNewIssue notifies about a new issue and reports whether it was handled,
+// either by sending it, because another view already did or because
+// notifications are paused
+func (s *Service) deliverNewIssue(ctx context.Context, issue issue.Issue) bool {
+	if s.dedup != nil && !s.dedup.claim(issue.HTMLURL) {
+		s.trace(issue, "already notified recently by another view, skipping")
+		return true
+	}
+	if s.titleDedup != nil && !s.titleDedup.claim(issue.Title) {
+		s.trace(issue, "title duplicates a recently notified issue, skipping")
+		s.logger.Info("Skipping issue whose title duplicates a recently notified issue", "event", "new_issue", "issue_number", issue.Number, "title", issue.Title)
+		return true
+	}
+
+	if s.linkedPRs != nil {
+		issue = s.withLinkedPullRequests(ctx, issue)
+	}
+
+	if err := s.waitNotifyDelay(ctx); err != nil {
+		if s.dedup != nil {
+			s.dedup.release(issue.HTMLURL)
+		}
+		if s.titleDedup != nil {
+			s.titleDedup.release(issue.Title)
+		}
+		return false
+	}
+	if s.openIssues != nil {
+		s.openIssues.Opened(issue.HTMLURL)
+	}
+	err := s.notifyWithRetry(ctx, func() error { return s.issueNotifier.NotifyNewIssue(issue) })
+	if errors.Is(err, notifier.ErrPaused) {
+		// Handled quietly, so the cursor still moves past it
+		s.trace(issue, "notifications are paused, skipping")
+		return true
+	}
+	if errors.Is(err, notifier.ErrAlreadySent) {
+		s.logger.Info("Skipping issue already notified before a restart", "event", "new_issue", "issue_number", issue.Number)
+		return true
+	}
+	if notifier.IsPartial(err) {
+		// Counted as delivered, since the next poll would notify the channels
+		// that already succeeded again
+		s.logger.Warn("Notification reached only some channels", "event", "new_issue", "issue_number", issue.Number, "error", err)
+		err = nil
+	}
+	if err != nil {
+		s.logger.Error("Error sending notification", "event", "new_issue", "issue_number", issue.Number, "error", err)
+		if s.dedup != nil {
+			s.dedup.release(issue.HTMLURL)
+		}
+		if s.titleDedup != nil {
+			s.titleDedup.release(issue.Title)
+		}
+		return false
+	}
+	s.logger.Info("Sent notification for new issue", "event", "new_issue", "issue_number", issue.Number, "title", issue.Title)
+	s.delivered.Add(1)
+	if s.recent != nil {
+		s.recent.add(issue, s.name)
+	}
+
+	if s.reminders != nil {
+		s.reminders.track(issue)
+	}
+	return true
+}
+
+// waitNotifyDelay spaces new-issue notifications at least notifyDelay apart so
+// a burst doesn't flood the desktop
+func (s *Service) waitNotifyDelay(ctx context.Context) error {
+	s.notifyMutex.Lock()
+	defer s.notifyMutex.Unlock()
+
+	if wait := s.notifyDelay - time.Since(s.lastNotifyTime); wait > 0 {
+		if err := ctxutil.Sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+	s.lastNotifyTime = time.Now()
+	return nil
+}
+
+func (s *Service) checkForStateChanges(ctx context.Context) error {
+	if err := s.limiter.Wait(ctx); err != nil {
+		return fmt.Errorf("rate limit error: %v", err)
+	}
+
+	issues, err := s.repo.FetchRecentlyUpdatedIssues(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.traceMissing(issues, "state change check")
+	now := time.Now()
+	defer s.pruneKnownStates()
+	for _, issue := range issues {
+		last, known := s.knownStates[issue.ID]
+		previous := last.state
+		s.knownStates[issue.ID] = knownState{state: issue.State, seen: now}
+
+		// The first sighting only records the state so there is something to compare against
+		if !known {
+			s.trace(issue, "first sighting, recording state %q", issue.State)
+			continue
+		}
+		if previous == issue.State {
+			s.trace(issue, "state unchanged (%s), skipping", issue.State)
+			continue
+		}
+
+		transition := Transition{From: previous, To: issue.State}
+		if !s.transitions[transition] {
+			s.trace(issue, "transition %s is not watched, skipping", transition)
+			continue
+		}
+		s.trace(issue, "transition %s is watched, notifying", transition)
+
+		if err := s.issueNotifier.NotifyStateChange(issue, previous, issue.State); err != nil {
+			s.logger.Error("Error sending state change notification", "event", "state_change", "issue_number", issue.Number, "error", err)
+			continue
+		}
+		s.logger.Info("Sent state change notification", "event", "state_change", "issue_number", issue.Number, "transition", transition.String())
+	}
+
+	return nil
+}
+
+// trace logs a decision about issue when it is the issue being traced
+func (s *Service) trace(issue issue.Issue, format string, args ...any) {
+	if s.traceIssue == 0 || issue.Number != s.traceIssue {
+		return
+	}
+	s.logger.Info(fmt.Sprintf("[trace #%d] "+format, append([]any{issue.Number}, args...)...), "event", "trace", "issue_number", issue.Number)
+}
+
+// traceMissing logs when the traced issue is absent from a fetch, e.g. because
+// it is a pull request, beyond the fetched pages, or not matched by the query
+func (s *Service) traceMissing(issues []issue.Issue, check string) {
+	if s.traceIssue == 0 {
+		return
+	}
+	for _, issue := range issues {
+		if issue.Number == s.traceIssue {
+			return
+		}
+	}
+	s.logger.Info(fmt.Sprintf("[trace #%d] not among the %d issues fetched for the %s", s.traceIssue, len(issues), check), "event", "trace", "issue_number", s.traceIssue)
+}
+
+// poll runs one check and records it in the metrics. A panic in the check is
+// recovered and reported as an error so a bug in an optional feature doesn't
+// take the whole process down.
+func (s *Service) poll(ctx context.Context) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.logger.Error("Recovered from panic during poll", "event", "panic", "panic", fmt.Sprint(r), "stack", string(debug.Stack()))
+			s.panics++
+			if s.metrics != nil {
+				s.metrics.Panics.Add(1)
+			}
+			err = fmt.Errorf("panic during poll: %v", r)
+		} else {
+			s.panics = 0
+		}
+		if s.metrics != nil {
+			s.metrics.RecordPoll(err)
+		}
+		s.recordStatus(err)
+		s.reportRateLimit()
+		s.logger.Debug("Poll finished", "event", "poll", "fetched", s.lastFetched, "last_check_id", s.lastCheckID)
+	}()
+	s.logger.Debug("Checking for new issues", "event", "poll")
+	return s.checkForNewIssues(ctx)
+}
+
+// reportRateLimit publishes the remaining API quota and warns when it runs low
+func (s *Service) reportRateLimit() {
+	source, ok := s.repo.(RateLimitSource)
+	if !ok {
+		return
+	}
+	limit, ok := source.RateLimit()
+	if !ok {
+		return
+	}
+
+	if s.metrics != nil {
+		s.metrics.RateLimitRemaining.Store(int64(limit.Remaining))
+	}
+	if limit.Limit > 0 && limit.Remaining*10 < limit.Limit {
+		s.logger.Warn("GitHub API rate limit running low", "event", "rate_limit",
+			"remaining", limit.Remaining, "limit", limit.Limit, "reset", limit.Reset.Format(time.Kitchen))
+	}
+}
+
+// RunOnce runs a single poll, for use from cron jobs and CI. It returns an
+// error if the poll failed or any new-issue notification could not be sent,
+// and reports the result to the initial check hook like Start does.
+//
+// Runs are usually further apart than the grace period, so issues are held
+// from when they were created rather than when this run first saw them. Those
+// still within the period are left above the persisted cursor and handled by
+// a later run.
+func (s *Service) RunOnce(ctx context.Context) error {
+	if s.grace != nil {
+		s.grace.fromCreated = true
+	}
+
+	err := s.poll(ctx)
+	if err == nil && s.lastFailed > 0 {
+		err = fmt.Errorf("%d new issue notifications failed", s.lastFailed)
+	}
+	if s.grace != nil && len(s.grace.held) > 0 {
+		s.logger.Info("Leaving issues held for the grace period to a later run", "event", "grace_period", "count", len(s.grace.held))
+	}
+	if s.onInitialCheck != nil {
+		s.onInitialCheck(s.lastFetched, err)
+	}
+	return err
+}
+
+// Start begins the notification service. Each poll runs in its own goroutine
+// under the service's WaitGroup so a slow poll doesn't hold up shutdown; a tick
+// arriving while the previous poll is still running is skipped, so lastCheckID
+// is only ever touched by one poll at a time.
 func (s *Service) Start(ctx context.Context) error {
-	log.Printf("Starting GitHub issues notification service...")
-	log.Printf("Poll interval: %v", s.pollInterval)
+	s.logger.Info("Starting GitHub issues notification service", "event", "start", "poll_interval", s.pollInterval.String())
 
-	// Initial check
-	if err := s.checkForNewIssues(ctx); err != nil {
-		log.Printf("Error during initial check: %v", err)
+	done := make(chan error, 1)
+	running := false
+	startPoll := func() {
+		running = true
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			done <- s.poll(ctx)
+		}()
 	}
 
-	ticker := time.NewTicker(s.pollInterval)
+	// Initial check
+	startPoll()
+	initial := true
+
+	interval := s.pollInterval
+	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
 	for {
 		select {
 		case <-ticker.C:
-			if err := s.checkForNewIssues(ctx); err != nil {
-				log.Printf("Error checking for new issues: %v", err)
+			if running {
+				s.logger.Debug("Previous poll still running, skipping this one", "event", "poll")
+				continue
+			}
+			startPoll()
+		case err := <-done:
+			running = false
+			if initial {
+				initial = false
+				if err != nil {
+					s.logger.Error("Error during initial check", "event", "poll", "error", err)
+				}
+				if s.onInitialCheck != nil {
+					s.onInitialCheck(s.lastFetched, err)
+				}
+			} else if err != nil {
+				s.logger.Error("Error checking for new issues", "event", "poll", "error", err)
+			}
+			if s.panics >= config.MaxConsecutivePanics {
+				return fmt.Errorf("giving up after %d consecutive panics", s.panics)
+			}
+			if s.adaptive != nil {
+				if next := s.nextInterval(interval); next != interval {
+					interval = next
+					ticker.Reset(interval)
+				}
 			}
 		case <-ctx.Done():
-			log.Println("Context cancelled, stopping service...")
+			s.logger.Info("Context cancelled, stopping service", "event", "stop")
+			s.drain()
 			return nil
 		case <-s.shutdownChan:
-			log.Println("Shutdown requested, stopping service...")
+			s.logger.Info("Shutdown requested, stopping service", "event", "stop")
 			return nil
 		}
 	}
 }
 
-// Stop gracefully stops the notification service
+// Stop gracefully stops the notification service. It waits up to
+// config.ShutdownTimeout for a running poll to finish delivering its
+// notifications and saving the last seen issue.
 func (s *Service) Stop() {
 	close(s.shutdownChan)
+	s.drain()
+}
+
+// drain waits up to config.ShutdownTimeout for running polls and logs how many
+// notifications they sent in the meantime
+func (s *Service) drain() {
+	before := s.delivered.Load()
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		s.logger.Info("Drained pending notifications", "event", "stop", "count", s.delivered.Load()-before)
+	case <-time.After(config.ShutdownTimeout):
+		s.logger.Warn("Timed out waiting for pending notifications", "event", "stop", "drained", s.delivered.Load()-before, "timeout", config.ShutdownTimeout.String())
+	}
 }
@@ -0,0 +1,446 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gitnotifier/internal/issue"
+	"gitnotifier/internal/notifier"
+	"gitnotifier/internal/repository"
+	"io"
+	"log/slog"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// MockRepository implements repository.IssueRepository, returning one scripted
+// slice of issues per call and repeating the last once the script runs out
+type MockRepository struct {
+	Latest  [][]issue.Issue
+	Updated [][]issue.Issue
+	Err     error // Returned by every fetch when set
+
+	latestCalls  int
+	updatedCalls int
+}
+
+func (m *MockRepository) FetchLatestIssues(ctx context.Context) ([]issue.Issue, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	return scripted(m.Latest, &m.latestCalls), nil
+}
+
+func (m *MockRepository) FetchRecentlyUpdatedIssues(ctx context.Context) ([]issue.Issue, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	return scripted(m.Updated, &m.updatedCalls), nil
+}
+
+func scripted(script [][]issue.Issue, calls *int) []issue.Issue {
+	if len(script) == 0 {
+		return nil
+	}
+	i := min(*calls, len(script)-1)
+	*calls++
+	return script[i]
+}
+
+// sentNotification is one call recorded by recordingNotifier
+type sentNotification struct {
+	Title, Message, URL string
+}
+
+// recordingNotifier is a fake notifier.Notifier that records what was sent.
+// When fail is set and returns an error for a notification, it is not recorded.
+type recordingNotifier struct {
+	mu   sync.Mutex
+	sent []sentNotification
+	fail func(n sentNotification) error
+}
+
+func (r *recordingNotifier) Notify(title, message, url string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n := sentNotification{Title: title, Message: message, URL: url}
+	if r.fail != nil {
+		if err := r.fail(n); err != nil {
+			return err
+		}
+	}
+	r.sent = append(r.sent, n)
+	return nil
+}
+
+// urls returns the URLs notified so far, in order
+func (r *recordingNotifier) urls() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	urls := make([]string, 0, len(r.sent))
+	for _, n := range r.sent {
+		urls = append(urls, n.URL)
+	}
+	return urls
+}
+
+// failURLs fails every notification for one of urls
+func failURLs(urls ...string) func(sentNotification) error {
+	return func(n sentNotification) error {
+		if slices.Contains(urls, n.URL) {
+			return errors.New("notifier unavailable")
+		}
+		return nil
+	}
+}
+
+// testIssue is an open issue whose ID is 100 more than its number
+func testIssue(number int) issue.Issue {
+	return issue.Issue{
+		ID:        100 + number,
+		Number:    number,
+		Title:     fmt.Sprintf("Issue %d", number),
+		State:     "open",
+		CreatedAt: time.Date(2024, 1, 1, 0, number, 0, 0, time.UTC),
+		HTMLURL:   issueURL(number),
+	}
+}
+
+func issueURL(number int) string {
+	return fmt.Sprintf("https://github.com/owner/repo/issues/%d", number)
+}
+
+// testIssues returns the issues with the given numbers in the API's newest-first order
+func testIssues(numbers ...int) []issue.Issue {
+	issues := make([]issue.Issue, 0, len(numbers))
+	for _, n := range slices.Backward(numbers) {
+		issues = append(issues, testIssue(n))
+	}
+	return issues
+}
+
+// newTestService creates a service without notification spacing or retries
+// that logs nowhere, starting from lastCheckID
+func newTestService(repo *MockRepository, n *recordingNotifier, lastCheckID int, opts ...Option) *Service {
+	opts = append([]Option{
+		WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		WithNotifyDelay(0),
+		WithNotifyRetries(0),
+	}, opts...)
+	s := NewService(repo, n, time.Minute, opts...)
+	s.lastCheckID = lastCheckID
+	return s
+}
+
+func TestCheckForNewIssuesNotifiesOnlyNewIssues(t *testing.T) {
+	tests := []struct {
+		name        string
+		lastCheckID int
+		fetched     []issue.Issue
+		want        []string
+	}{
+		{"first poll notifies everything", 0, testIssues(1, 2, 3), []string{issueURL(3), issueURL(2), issueURL(1)}},
+		{"only issues above the cursor", 102, testIssues(1, 2, 3, 4), []string{issueURL(4), issueURL(3)}},
+		{"the cursor issue itself is not new", 103, testIssues(2, 3), nil},
+		{"nothing fetched", 105, nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := &recordingNotifier{}
+			s := newTestService(&MockRepository{Latest: [][]issue.Issue{tt.fetched}}, n, tt.lastCheckID)
+
+			if err := s.checkForNewIssues(context.Background()); err != nil {
+				t.Fatalf("checkForNewIssues: %v", err)
+			}
+			if got := n.urls(); !slices.Equal(got, tt.want) {
+				t.Errorf("notified %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckForNewIssuesAdvancesLastCheckID(t *testing.T) {
+	tests := []struct {
+		name        string
+		lastCheckID int
+		polls       [][]issue.Issue
+		want        int
+	}{
+		{"advances to the newest issue", 0, [][]issue.Issue{testIssues(1, 2, 3)}, 103},
+		{"keeps advancing across polls", 0, [][]issue.Issue{testIssues(1), testIssues(1, 2, 3)}, 103},
+		{"unchanged without new issues", 104, [][]issue.Issue{testIssues(3, 4)}, 104},
+		{"never moves backwards", 110, [][]issue.Issue{testIssues(2, 3)}, 110},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService(&MockRepository{Latest: tt.polls}, &recordingNotifier{}, tt.lastCheckID)
+
+			for range tt.polls {
+				if err := s.checkForNewIssues(context.Background()); err != nil {
+					t.Fatalf("checkForNewIssues: %v", err)
+				}
+			}
+			if s.lastCheckID != tt.want {
+				t.Errorf("lastCheckID = %d, want %d", s.lastCheckID, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckForNewIssuesContinuesAfterNotifyError(t *testing.T) {
+	tests := []struct {
+		name       string
+		fetched    []issue.Issue
+		failing    []string
+		want       []string
+		wantCursor int
+	}{
+		{
+			name:       "newest fails",
+			fetched:    testIssues(1, 2, 3),
+			failing:    []string{issueURL(3)},
+			want:       []string{issueURL(2), issueURL(1)},
+			wantCursor: 102,
+		},
+		{
+			name:       "middle fails",
+			fetched:    testIssues(1, 2, 3),
+			failing:    []string{issueURL(2)},
+			want:       []string{issueURL(3), issueURL(1)},
+			wantCursor: 101,
+		},
+		{
+			name:       "several fail",
+			fetched:    testIssues(1, 2, 3, 4),
+			failing:    []string{issueURL(1), issueURL(3)},
+			want:       []string{issueURL(4), issueURL(2)},
+			wantCursor: 100,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := &recordingNotifier{fail: failURLs(tt.failing...)}
+			s := newTestService(&MockRepository{Latest: [][]issue.Issue{tt.fetched}}, n, 100)
+
+			if err := s.checkForNewIssues(context.Background()); err != nil {
+				t.Fatalf("checkForNewIssues: %v", err)
+			}
+			if got := n.urls(); !slices.Equal(got, tt.want) {
+				t.Errorf("notified %v, want %v", got, tt.want)
+			}
+			if s.lastCheckID != tt.wantCursor {
+				t.Errorf("lastCheckID = %d, want %d so the failed issues are retried", s.lastCheckID, tt.wantCursor)
+			}
+		})
+	}
+}
+
+// sentLog is an in-memory notifier.SentLog
+type sentLog map[int]bool
+
+func (l sentLog) Seen(id int) bool { return l[id] }
+
+func (l sentLog) Record(id int) error {
+	l[id] = true
+	return nil
+}
+
+func TestCheckForNewIssuesWhilePaused(t *testing.T) {
+	attempts := 0
+	n := &recordingNotifier{fail: func(sentNotification) error {
+		attempts++
+		return notifier.ErrPaused
+	}}
+	s := newTestService(&MockRepository{Latest: [][]issue.Issue{testIssues(1, 2)}}, n, 100, WithNotifyRetries(3))
+	sent := sentLog{}
+	s.issueNotifier.Sent = sent
+
+	if err := s.checkForNewIssues(context.Background()); err != nil {
+		t.Fatalf("checkForNewIssues: %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("made %d attempts, want one per issue without retries", attempts)
+	}
+	if s.lastCheckID != 102 {
+		t.Errorf("lastCheckID = %d, want 102 so the state keeps advancing", s.lastCheckID)
+	}
+	if len(sent) != 0 {
+		t.Errorf("recorded %v as sent while paused", sent)
+	}
+	if got := s.delivered.Load(); got != 0 {
+		t.Errorf("counted %d deliveries while paused", got)
+	}
+}
+
+func TestCheckForNewIssuesSkipsAlreadySent(t *testing.T) {
+	n := &recordingNotifier{}
+	s := newTestService(&MockRepository{Latest: [][]issue.Issue{testIssues(1, 2)}}, n, 100, WithNotifyRetries(3))
+	s.issueNotifier.Sent = sentLog{101: true}
+
+	if err := s.checkForNewIssues(context.Background()); err != nil {
+		t.Fatalf("checkForNewIssues: %v", err)
+	}
+	if got := n.urls(); !slices.Equal(got, []string{issueURL(2)}) {
+		t.Errorf("notified %v, want only the issue not sent before", got)
+	}
+	if s.lastCheckID != 102 {
+		t.Errorf("lastCheckID = %d, want 102", s.lastCheckID)
+	}
+	if got := s.delivered.Load(); got != 1 {
+		t.Errorf("counted %d deliveries, want the skipped issue left out", got)
+	}
+}
+
+func TestRunOnceFailsWhenNotificationsFail(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		fail    func(sentNotification) error
+		wantErr bool
+	}{
+		{"delivered", nil, false},
+		{"failed", failURLs(issueURL(2)), true},
+		{"paused", func(sentNotification) error { return notifier.ErrPaused }, false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var hookErr error
+			hooked := false
+			n := &recordingNotifier{fail: tt.fail}
+			s := newTestService(&MockRepository{Latest: [][]issue.Issue{testIssues(1, 2)}}, n, 100,
+				WithInitialCheckHook(func(fetched int, err error) { hooked, hookErr = true, err }))
+
+			err := s.RunOnce(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RunOnce = %v, want error %v", err, tt.wantErr)
+			}
+			if !hooked || hookErr != err {
+				t.Errorf("initial check hook called %v with %v, want %v", hooked, hookErr, err)
+			}
+		})
+	}
+}
+
+func TestCheckForNewIssuesPartialFailure(t *testing.T) {
+	tests := []struct {
+		name     string
+		failures int // Times the flaky channel fails before it recovers
+		want     int // Notifications it receives
+	}{
+		{"recovers within the retries", 2, 1},
+		{"keeps failing", 10, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			desktop := &recordingNotifier{}
+			failures := tt.failures
+			flaky := &recordingNotifier{fail: func(sentNotification) error {
+				if failures > 0 {
+					failures--
+					return errors.New("connection reset")
+				}
+				return nil
+			}}
+			m := notifier.NewMultiNotifier([]notifier.NamedNotifier{
+				{Name: "desktop", Notifier: desktop},
+				{Name: "slack", Notifier: flaky},
+			}, 0)
+			repo := &MockRepository{Latest: [][]issue.Issue{testIssues(1), testIssues(1)}}
+			s := NewService(repo, m, time.Minute,
+				WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))), WithNotifyDelay(0), WithNotifyRetries(2))
+			s.retryDelay = 0
+			s.lastCheckID = 100
+			sent := sentLog{}
+			s.issueNotifier.Sent = sent
+
+			for range 2 {
+				if err := s.checkForNewIssues(context.Background()); err != nil {
+					t.Fatalf("checkForNewIssues: %v", err)
+				}
+			}
+			if got := len(desktop.urls()); got != 1 {
+				t.Errorf("desktop got %d notifications, want one without repeats for the other channel", got)
+			}
+			if got := len(flaky.urls()); got != tt.want {
+				t.Errorf("flaky channel got %d notifications, want %d", got, tt.want)
+			}
+			if s.lastCheckID != 101 || !sent[101] {
+				t.Errorf("lastCheckID = %d, recorded sent %v, want the issue counted as delivered", s.lastCheckID, sent)
+			}
+		})
+	}
+}
+
+// countingCommentFetcher is a CommentFetcher that counts its calls
+type countingCommentFetcher struct {
+	calls int
+}
+
+func (c *countingCommentFetcher) FetchRecentComments(ctx context.Context) ([]repository.Comment, error) {
+	c.calls++
+	return nil, nil
+}
+
+func TestCheckForNewIssuesContinuesAfterWatchError(t *testing.T) {
+	denied := func(context.Context) ([]repository.Advisory, error) {
+		return nil, fmt.Errorf("cannot read security advisories (status 403): %w", repository.ErrSecurityAccessDenied)
+	}
+	unavailable := func(context.Context) ([]repository.Advisory, error) {
+		return nil, errors.New("GitHub API returned status code: 502")
+	}
+	alerts := func(context.Context) ([]repository.Advisory, error) {
+		return []repository.Advisory{{ID: "dependabot-1", Source: "dependabot"}}, nil
+	}
+	tests := []struct {
+		name         string
+		fetchers     []AdvisoryFetcher
+		wantFetchers int // Advisory fetchers left after the poll; 0 turns the watch off
+	}{
+		{"access denied", []AdvisoryFetcher{denied}, 0},
+		{"access denied to one endpoint", []AdvisoryFetcher{denied, alerts}, 1},
+		{"transient error", []AdvisoryFetcher{unavailable}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			comments := &countingCommentFetcher{}
+			repo := &MockRepository{Latest: [][]issue.Issue{testIssues(1)}}
+			s := newTestService(repo, &recordingNotifier{}, 100, WithAdvisoryWatch(tt.fetchers...), WithCommentWatch(comments, false))
+
+			if err := s.checkForNewIssues(context.Background()); err != nil {
+				t.Fatalf("checkForNewIssues: %v, want a failed watch not to fail the poll", err)
+			}
+			if comments.calls != 1 {
+				t.Errorf("comments fetched %d times, want the later watches to run", comments.calls)
+			}
+			got := 0
+			if s.advisories != nil {
+				got = len(s.advisories.fetchers)
+			}
+			if got != tt.wantFetchers {
+				t.Errorf("%d advisory fetchers left, want %d", got, tt.wantFetchers)
+			}
+		})
+	}
+}
+
+func TestCheckForNewIssuesDeliversAfterCancel(t *testing.T) {
+	n := &recordingNotifier{}
+	repo := &MockRepository{Latest: [][]issue.Issue{testIssues(1)}}
+	s := newTestService(repo, n, 100, WithNotifyDelay(200*time.Millisecond))
+	s.lastNotifyTime = time.Now()
+
+	// Cancelled while the poll waits out the notification delay
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	if err := s.checkForNewIssues(ctx); err != nil {
+		t.Fatalf("checkForNewIssues: %v", err)
+	}
+	if got := n.urls(); !slices.Equal(got, []string{issueURL(1)}) {
+		t.Errorf("notified %v, want the in-progress notification delivered", got)
+	}
+	if s.lastCheckID != 101 {
+		t.Errorf("lastCheckID = %d, want 101", s.lastCheckID)
+	}
+}
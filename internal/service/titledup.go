@@ -0,0 +1,127 @@
+package service
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// TitleDedup suppresses new-issue notifications whose title matches one
+// notified within the window, which catches bots opening near-identical issues
+type TitleDedup struct {
+	window time.Duration
+	// similarity is the minimum similarity ratio in (0, 1] for two titles to
+	// count as duplicates; 1 requires an exact match after normalization
+	similarity float64
+	mu         sync.Mutex
+	recent     []recentTitle
+}
+
+type recentTitle struct {
+	title string
+	at    time.Time
+}
+
+// NewTitleDedup creates a TitleDedup that remembers titles for window. A
+// similarity outside (0, 1] means exact matching.
+func NewTitleDedup(window time.Duration, similarity float64) *TitleDedup {
+	if similarity <= 0 || similarity > 1 {
+		similarity = 1
+	}
+	return &TitleDedup{
+		window:     window,
+		similarity: similarity,
+	}
+}
+
+// WithTitleDedup skips new issues whose title duplicates a recently notified one
+func WithTitleDedup(d *TitleDedup) Option {
+	return func(s *Service) {
+		s.titleDedup = d
+	}
+}
+
+// claim records title and reports whether no similar title was claimed within the window
+func (d *TitleDedup) claim(title string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := time.Now()
+	kept := d.recent[:0]
+	for _, r := range d.recent {
+		if now.Sub(r.at) < d.window {
+			kept = append(kept, r)
+		}
+	}
+	d.recent = kept
+
+	title = normalizeTitle(title)
+	for _, r := range d.recent {
+		if d.matches(r.title, title) {
+			return false
+		}
+	}
+	d.recent = append(d.recent, recentTitle{title: title, at: now})
+	return true
+}
+
+// release forgets title so a failed notification doesn't suppress a retry
+func (d *TitleDedup) release(title string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	title = normalizeTitle(title)
+	for i := len(d.recent) - 1; i >= 0; i-- {
+		if d.recent[i].title == title {
+			d.recent = append(d.recent[:i], d.recent[i+1:]...)
+			return
+		}
+	}
+}
+
+func (d *TitleDedup) matches(a, b string) bool {
+	if a == b {
+		return true
+	}
+	if d.similarity >= 1 {
+		return false
+	}
+	return similarity(a, b) >= d.similarity
+}
+
+// normalizeTitle lowercases title and collapses runs of whitespace
+func normalizeTitle(title string) string {
+	return strings.Join(strings.Fields(strings.ToLower(title)), " ")
+}
+
+// similarity returns 1 minus the edit distance between a and b relative to
+// the longer of the two, so identical strings score 1
+func similarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longest := max(len(ra), len(rb))
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+// levenshtein computes the edit distance between a and b using two rows
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
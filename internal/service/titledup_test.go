@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"gitnotifier/internal/issue"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestTitleDedupClaim(t *testing.T) {
+	tests := []struct {
+		name        string
+		similarity  float64
+		first, next string
+		want        bool // Whether next may still be notified
+	}{
+		{"exact duplicate", 1, "Dependency update failed", "Dependency update failed", false},
+		{"differs in case and spacing", 1, "Dependency update failed", "  dependency   UPDATE failed", false},
+		{"near duplicate with exact matching", 1, "Bump lodash to 4.17.20", "Bump lodash to 4.17.21", true},
+		{"near duplicate", 0.9, "Bump lodash to 4.17.20", "Bump lodash to 4.17.21", false},
+		{"different title", 0.9, "Bump lodash to 4.17.20", "Crash on start", true},
+		{"out of range similarity is exact", 1.5, "Bump lodash to 4.17.20", "Bump lodash to 4.17.21", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewTitleDedup(time.Hour, tt.similarity)
+			if !d.claim(tt.first) {
+				t.Fatalf("claim(%q) on an empty TitleDedup = false", tt.first)
+			}
+			if got := d.claim(tt.next); got != tt.want {
+				t.Errorf("claim(%q) after %q = %v, want %v", tt.next, tt.first, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTitleDedupWindowAndRelease(t *testing.T) {
+	d := NewTitleDedup(time.Hour, 1)
+	d.claim("Crash on start")
+	d.release("Crash on start")
+	if !d.claim("Crash on start") {
+		t.Error("a released title is still treated as a duplicate")
+	}
+
+	d.recent[0].at = time.Now().Add(-2 * time.Hour)
+	if !d.claim("Crash on start") {
+		t.Error("a title notified before the window is still treated as a duplicate")
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"", "", 1},
+		{"abcd", "abcd", 1},
+		{"abcd", "abce", 0.75},
+		{"abcd", "", 0},
+		{"kitten", "sitting", 1 - 3.0/7},
+	}
+	for _, tt := range tests {
+		if got := similarity(tt.a, tt.b); got != tt.want {
+			t.Errorf("similarity(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestCheckForNewIssuesSkipsDuplicateTitles(t *testing.T) {
+	fetched := testIssues(1, 2, 3)
+	fetched[0].Title = "Renovate dashboard"  // #3
+	fetched[1].Title = "renovate  Dashboard" // #2
+	n := &recordingNotifier{}
+	s := newTestService(&MockRepository{Latest: [][]issue.Issue{fetched}}, n, 100, WithTitleDedup(NewTitleDedup(time.Hour, 1)))
+
+	if err := s.checkForNewIssues(context.Background()); err != nil {
+		t.Fatalf("checkForNewIssues: %v", err)
+	}
+	if got, want := n.urls(), []string{issueURL(3), issueURL(1)}; !slices.Equal(got, want) {
+		t.Errorf("notified %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"fmt"
+	"gitnotifier/config"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Transition describes an issue moving from one state to another
+type Transition struct {
+	From string
+	To   string
+}
+
+func (t Transition) String() string {
+	return t.From + "->" + t.To
+}
+
+var validStates = map[string]bool{"open": true, "closed": true}
+
+// knownState is the last state seen of an issue, to compare the next one against
+type knownState struct {
+	state string
+	seen  time.Time
+}
+
+// pruneKnownStates forgets the issues seen longest ago once more than
+// config.MaxKnownStates are tracked, so a long-running service doesn't grow
+// without bound. A forgotten issue that is updated again is treated as a
+// first sighting.
+func (s *Service) pruneKnownStates() {
+	excess := len(s.knownStates) - config.MaxKnownStates
+	if excess <= 0 {
+		return
+	}
+	ids := slices.Collect(maps.Keys(s.knownStates))
+	slices.SortFunc(ids, func(a, b int) int {
+		return s.knownStates[a].seen.Compare(s.knownStates[b].seen)
+	})
+	for _, id := range ids[:excess] {
+		delete(s.knownStates, id)
+	}
+}
+
+// ParseTransitions parses a comma-separated list of transitions such as
+// "open->closed,closed->open"
+func ParseTransitions(spec string) ([]Transition, error) {
+	var transitions []Transition
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		from, to, ok := strings.Cut(part, "->")
+		if !ok {
+			return nil, fmt.Errorf("invalid transition %q. Expected format 'from->to'", part)
+		}
+
+		from = strings.ToLower(strings.TrimSpace(from))
+		to = strings.ToLower(strings.TrimSpace(to))
+		if !validStates[from] || !validStates[to] {
+			return nil, fmt.Errorf("invalid transition %q. States must be 'open' or 'closed'", part)
+		}
+		if from == to {
+			return nil, fmt.Errorf("invalid transition %q. From and to states must differ", part)
+		}
+
+		transitions = append(transitions, Transition{From: from, To: to})
+	}
+	return transitions, nil
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"gitnotifier/config"
+	"gitnotifier/internal/issue"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestParseTransitions(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    []Transition
+		wantErr bool
+	}{
+		{spec: "open->closed", want: []Transition{{"open", "closed"}}},
+		{spec: " Open -> CLOSED , closed->open,", want: []Transition{{"open", "closed"}, {"closed", "open"}}},
+		{spec: "", want: nil},
+		{spec: "open-closed", wantErr: true},
+		{spec: "open->merged", wantErr: true},
+		{spec: "open->open", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := ParseTransitions(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTransitions(%q) error = %v, want error %v", tt.spec, err, tt.wantErr)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ParseTransitions(%q) = %v, want %v", tt.spec, got, tt.want)
+			}
+		})
+	}
+}
+
+// withState returns issue in the given state
+func withState(i issue.Issue, state string) issue.Issue {
+	i.State = state
+	return i
+}
+
+func TestCheckForStateChangesNotifiesWatchedTransitions(t *testing.T) {
+	// Issue 1 closes, issue 2 reopens and issue 3 stays open between the polls
+	before := []issue.Issue{testIssue(1), withState(testIssue(2), "closed"), testIssue(3)}
+	after := []issue.Issue{withState(testIssue(1), "closed"), testIssue(2), testIssue(3)}
+
+	tests := []struct {
+		spec string
+		want []string
+	}{
+		{"open->closed", []string{issueURL(1)}},
+		{"closed->open", []string{issueURL(2)}},
+		{"open->closed,closed->open", []string{issueURL(1), issueURL(2)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			transitions, err := ParseTransitions(tt.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			n := &recordingNotifier{}
+			repo := &MockRepository{Updated: [][]issue.Issue{before, after}}
+			s := newTestService(repo, n, 0, WithTransitions(transitions))
+
+			// The first check only records the states
+			for range 2 {
+				if err := s.checkForStateChanges(context.Background()); err != nil {
+					t.Fatalf("checkForStateChanges: %v", err)
+				}
+			}
+			if got := n.urls(); !slices.Equal(got, tt.want) {
+				t.Errorf("notified %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckForStateChangesIgnoresFirstSighting(t *testing.T) {
+	transitions, _ := ParseTransitions("open->closed,closed->open")
+	n := &recordingNotifier{}
+	repo := &MockRepository{Updated: [][]issue.Issue{{withState(testIssue(1), "closed")}}}
+	s := newTestService(repo, n, 0, WithTransitions(transitions))
+
+	if err := s.checkForStateChanges(context.Background()); err != nil {
+		t.Fatalf("checkForStateChanges: %v", err)
+	}
+	if got := n.urls(); len(got) > 0 {
+		t.Errorf("notified %v on the first sighting, want nothing", got)
+	}
+}
+
+func TestCheckForStateChangesForgetsOldestStates(t *testing.T) {
+	transitions, _ := ParseTransitions("open->closed")
+	repo := &MockRepository{Updated: [][]issue.Issue{{testIssue(1), testIssue(2)}}}
+	s := newTestService(repo, &recordingNotifier{}, 0, WithTransitions(transitions))
+
+	// Fill the map with issues seen an hour ago, the oldest first
+	start := time.Now().Add(-time.Hour)
+	for id := range config.MaxKnownStates {
+		s.knownStates[-id-1] = knownState{state: "open", seen: start.Add(time.Duration(id) * time.Millisecond)}
+	}
+	if err := s.checkForStateChanges(context.Background()); err != nil {
+		t.Fatalf("checkForStateChanges: %v", err)
+	}
+
+	if got := len(s.knownStates); got != config.MaxKnownStates {
+		t.Errorf("tracking %d states, want %d", got, config.MaxKnownStates)
+	}
+	for _, id := range []int{testIssue(1).ID, testIssue(2).ID, -config.MaxKnownStates} {
+		if _, ok := s.knownStates[id]; !ok {
+			t.Errorf("forgot the state of recently seen issue %d", id)
+		}
+	}
+	for _, id := range []int{-1, -2} {
+		if _, ok := s.knownStates[id]; ok {
+			t.Errorf("kept the state of issue %d, the oldest seen", id)
+		}
+	}
+}
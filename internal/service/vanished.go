@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gitnotifier/internal/issue"
+	"gitnotifier/internal/repository"
+)
+
+// vanishedWatch detects open issues that disappear from the open issue list
+// without being closed, which happens when they are transferred or deleted
+type vanishedWatch struct {
+	fetcher IssueFetcher
+	open    map[int]issue.Issue // Open issues from the previous poll, keyed by number
+}
+
+// WithVanishedDetection notifies when a previously fetched open issue is
+// transferred to another repository or deleted
+func WithVanishedDetection(fetcher IssueFetcher) Option {
+	return func(s *Service) {
+		s.vanished = &vanishedWatch{fetcher: fetcher}
+	}
+}
+
+// checkVanished compares the open issues from this poll against the previous
+// one. Missing issues are looked up individually, since they may also have been
+// closed or simply pushed past the fetched pages.
+func (s *Service) checkVanished(ctx context.Context, fetched []issue.Issue) error {
+	current := make(map[int]issue.Issue, len(fetched))
+	for _, i := range fetched {
+		current[i.Number] = i
+	}
+
+	previous := s.vanished.open
+	s.vanished.open = current
+	if previous == nil {
+		return nil
+	}
+
+	for number, tracked := range previous {
+		if _, ok := current[number]; ok {
+			continue
+		}
+
+		if err := s.limiter.Wait(ctx); err != nil {
+			return fmt.Errorf("rate limit error: %v", err)
+		}
+		_, err := s.vanished.fetcher.FetchIssue(ctx, number)
+
+		var transferred *repository.TransferredError
+		switch {
+		case err == nil:
+			// Closed or no longer within the fetched pages
+			s.trace(tracked, "no longer in the open issue list but still exists")
+			continue
+		case errors.As(err, &transferred):
+			err = s.issueNotifier.NotifyVanished(tracked, transferred.HTMLURL)
+		case errors.Is(err, repository.ErrIssueNotFound):
+			err = s.issueNotifier.NotifyVanished(tracked, "")
+		default:
+			s.logger.Error("Error checking missing issue", "event", "vanished", "issue_number", number, "error", err)
+			continue
+		}
+
+		if s.openIssues != nil {
+			s.openIssues.Closed(tracked.HTMLURL)
+		}
+		if err != nil {
+			s.logger.Error("Error sending notification for vanished issue", "event", "vanished", "issue_number", number, "error", err)
+			continue
+		}
+		s.logger.Info("Sent notification for transferred or deleted issue", "event", "vanished", "issue_number", number)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"gitnotifier/internal/issue"
+	"gitnotifier/internal/repository"
+	"testing"
+)
+
+// stubFetcher implements IssueFetcher, returning the error for a number or
+// else the issue as still existing
+type stubFetcher map[int]error
+
+func (f stubFetcher) FetchIssue(ctx context.Context, number int) (*issue.Issue, error) {
+	if err := f[number]; err != nil {
+		return nil, err
+	}
+	i := withState(testIssue(number), "closed")
+	return &i, nil
+}
+
+func TestCheckVanishedNotifiesTransferredAndDeletedIssues(t *testing.T) {
+	movedURL := "https://github.com/owner/other/issues/7"
+	tests := []struct {
+		name      string
+		err       error // Returned when looking up the missing issue #2
+		wantTitle string
+		wantURL   string
+	}{
+		{"deleted", repository.ErrIssueNotFound, "Issue #2 was transferred or deleted", issueURL(2)},
+		{"transferred", &repository.TransferredError{HTMLURL: movedURL}, "Issue #2 was transferred", movedURL},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := &recordingNotifier{}
+			repo := &MockRepository{Latest: [][]issue.Issue{testIssues(1, 2, 3), testIssues(1, 3)}}
+			s := newTestService(repo, n, 110, WithVanishedDetection(stubFetcher{2: tt.err}))
+
+			for range 2 {
+				if err := s.checkForNewIssues(context.Background()); err != nil {
+					t.Fatalf("checkForNewIssues: %v", err)
+				}
+			}
+			if len(n.sent) != 1 {
+				t.Fatalf("sent %+v, want one notification for issue #2", n.sent)
+			}
+			if got := n.sent[0]; got.Title != tt.wantTitle || got.Message != "Issue 2" || got.URL != tt.wantURL {
+				t.Errorf("sent %+v, want %q for %s", got, tt.wantTitle, tt.wantURL)
+			}
+		})
+	}
+}
+
+func TestCheckVanishedIgnoresIssuesThatStillExist(t *testing.T) {
+	tests := []struct {
+		name  string
+		polls [][]issue.Issue
+		fetch stubFetcher
+	}{
+		// Closed issues drop out of the open list but can still be fetched
+		{"closed", [][]issue.Issue{testIssues(1, 2), testIssues(1)}, stubFetcher{}},
+		{"first poll", [][]issue.Issue{testIssues(1, 2)}, stubFetcher{1: repository.ErrIssueNotFound}},
+		{"still listed", [][]issue.Issue{testIssues(1, 2), testIssues(1, 2)}, stubFetcher{2: repository.ErrIssueNotFound}},
+		{"lookup fails", [][]issue.Issue{testIssues(1, 2), testIssues(1)}, stubFetcher{2: errors.New("connection reset")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := &recordingNotifier{}
+			s := newTestService(&MockRepository{Latest: tt.polls}, n, 110, WithVanishedDetection(tt.fetch))
+
+			for range tt.polls {
+				if err := s.checkForNewIssues(context.Background()); err != nil {
+					t.Fatalf("checkForNewIssues: %v", err)
+				}
+			}
+			if len(n.sent) != 0 {
+				t.Errorf("sent %+v, want nothing", n.sent)
+			}
+		})
+	}
+}
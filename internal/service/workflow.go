@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"gitnotifier/internal/repository"
+)
+
+// WorkflowRunFetcher defines the interface for fetching completed workflow runs
+type WorkflowRunFetcher interface {
+	FetchCompletedWorkflowRuns(ctx context.Context, branch string) ([]repository.WorkflowRun, error)
+}
+
+// workflowWatch notifies about completed workflow runs with selected conclusions
+type workflowWatch struct {
+	fetcher     WorkflowRunFetcher
+	branch      string
+	conclusions map[string]bool
+	lastRunID   int64
+}
+
+// WithWorkflowWatch notifies about runs on branch that finish with one of the
+// given conclusions, e.g. "failure". Runs completed before the first poll are
+// recorded without notifying.
+func WithWorkflowWatch(fetcher WorkflowRunFetcher, branch string, conclusions []string) Option {
+	return func(s *Service) {
+		w := &workflowWatch{
+			fetcher:     fetcher,
+			branch:      branch,
+			conclusions: make(map[string]bool, len(conclusions)),
+			lastRunID:   -1,
+		}
+		for _, c := range conclusions {
+			w.conclusions[c] = true
+		}
+		s.workflows = w
+	}
+}
+
+func (s *Service) checkWorkflowRuns(ctx context.Context) error {
+	if err := s.limiter.Wait(ctx); err != nil {
+		return fmt.Errorf("rate limit error: %v", err)
+	}
+
+	runs, err := s.workflows.fetcher.FetchCompletedWorkflowRuns(ctx, s.workflows.branch)
+	if err != nil {
+		return err
+	}
+
+	var newest int64
+	for _, run := range runs {
+		newest = max(newest, run.ID)
+	}
+
+	if s.workflows.lastRunID < 0 {
+		s.logger.Info("Tracking workflow runs", "event", "workflow_run", "branch", s.workflows.branch)
+		s.workflows.lastRunID = newest
+		return nil
+	}
+
+	for _, run := range runs {
+		if run.ID <= s.workflows.lastRunID || !s.workflows.conclusions[run.Conclusion] {
+			continue
+		}
+		if err := s.issueNotifier.NotifyWorkflowRun(run); err != nil {
+			s.logger.Error("Error sending notification for workflow run", "event", "workflow_run", "run_id", run.ID, "error", err)
+			continue
+		}
+		s.logger.Info("Sent notification for workflow run", "event", "workflow_run", "workflow", run.Name, "run_number", run.RunNumber, "conclusion", run.Conclusion)
+	}
+
+	s.workflows.lastRunID = max(s.workflows.lastRunID, newest)
+	return nil
+}
@@ -0,0 +1,103 @@
+package state
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// Encrypted files start with this header followed by the salt, nonce and ciphertext
+var encryptedHeader = []byte("GNENC1\n")
+
+const saltSize = 16
+
+var (
+	// ErrWrongKey is returned when an encrypted file cannot be decrypted with the configured key
+	ErrWrongKey = errors.New("unable to decrypt state file. Check STATE_ENCRYPTION_KEY")
+	// ErrKeyRequired is returned when an encrypted file is read without a key
+	ErrKeyRequired = errors.New("state file is encrypted. Set STATE_ENCRYPTION_KEY to read it")
+)
+
+// IsEncrypted reports whether data was produced by encrypt
+func IsEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, encryptedHeader)
+}
+
+// fileKey is the AES-GCM cipher for one state file. Deriving it from the
+// passphrase is deliberately slow, so it is done once per salt and reused for
+// every save; each save still uses a fresh nonce.
+type fileKey struct {
+	salt []byte
+	gcm  cipher.AEAD
+}
+
+// newFileKey derives the key for salt, or for a fresh random salt if salt is nil
+func newFileKey(passphrase string, salt []byte) (*fileKey, error) {
+	if salt == nil {
+		salt = make([]byte, saltSize)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, fmt.Errorf("error generating salt: %v", err)
+		}
+	}
+
+	key, err := scrypt.Key([]byte(passphrase), salt, 1<<15, 8, 1, 32)
+	if err != nil {
+		return nil, fmt.Errorf("error deriving encryption key: %v", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("error creating cipher: %v", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("error creating cipher: %v", err)
+	}
+	return &fileKey{salt: salt, gcm: gcm}, nil
+}
+
+// encrypt seals plaintext with a fresh nonce
+func (k *fileKey) encrypt(plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, k.gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("error generating nonce: %v", err)
+	}
+
+	out := append([]byte{}, encryptedHeader...)
+	out = append(out, k.salt...)
+	out = append(out, nonce...)
+	return k.gcm.Seal(out, nonce, plaintext, encryptedHeader), nil
+}
+
+// decrypt reverses encrypt, returning ErrWrongKey if authentication fails.
+// The key of cached is reused when the file has the same salt, otherwise it is
+// derived from passphrase; the key used is returned for later saves.
+func decrypt(passphrase string, cached *fileKey, data []byte) ([]byte, *fileKey, error) {
+	data = bytes.TrimPrefix(data, encryptedHeader)
+	if len(data) < saltSize {
+		return nil, nil, fmt.Errorf("encrypted state file is truncated")
+	}
+	salt, data := data[:saltSize], data[saltSize:]
+
+	k := cached
+	if k == nil || !bytes.Equal(k.salt, salt) {
+		var err error
+		if k, err = newFileKey(passphrase, bytes.Clone(salt)); err != nil {
+			return nil, nil, err
+		}
+	}
+	if len(data) < k.gcm.NonceSize() {
+		return nil, nil, fmt.Errorf("encrypted state file is truncated")
+	}
+	nonce, ciphertext := data[:k.gcm.NonceSize()], data[k.gcm.NonceSize():]
+
+	plaintext, err := k.gcm.Open(nil, nonce, ciphertext, encryptedHeader)
+	if err != nil {
+		return nil, nil, ErrWrongKey
+	}
+	return plaintext, k, nil
+}
@@ -0,0 +1,61 @@
+package state
+
+import (
+	"maps"
+	"slices"
+	"time"
+)
+
+// NotifiedLog keeps the IDs of issues recently notified by one view in the
+// state file, so a restart in the middle of a poll doesn't notify an issue
+// twice. IDs are forgotten after ttl, and only the size most recent are kept.
+type NotifiedLog struct {
+	store *Store
+	key   string // Key in the persisted state, e.g. "owner/repo"
+	ttl   time.Duration
+	size  int
+}
+
+// NewNotifiedLog creates a NotifiedLog persisted in store under key
+func NewNotifiedLog(store *Store, key string, ttl time.Duration, size int) *NotifiedLog {
+	return &NotifiedLog{store: store, key: key, ttl: ttl, size: size}
+}
+
+// Seen reports whether issue id was notified within the window
+func (l *NotifiedLog) Seen(id int) bool {
+	l.store.mu.Lock()
+	defer l.store.mu.Unlock()
+
+	at, ok := l.store.state.Repos[l.key].Notified[id]
+	return ok && time.Since(at) < l.ttl
+}
+
+// Record marks issue id as notified now, dropping expired and excess entries
+func (l *NotifiedLog) Record(id int) error {
+	return l.store.Update(func(st *State) {
+		if st.Repos == nil {
+			st.Repos = make(map[string]RepoState)
+		}
+		rs := st.Repos[l.key]
+		if rs.Notified == nil {
+			rs.Notified = make(map[int]time.Time)
+		}
+		now := time.Now()
+		rs.Notified[id] = now
+
+		for k, at := range rs.Notified {
+			if now.Sub(at) >= l.ttl {
+				delete(rs.Notified, k)
+			}
+		}
+		if excess := len(rs.Notified) - l.size; excess > 0 {
+			oldest := slices.SortedFunc(maps.Keys(rs.Notified), func(a, b int) int {
+				return rs.Notified[a].Compare(rs.Notified[b])
+			})
+			for _, k := range oldest[:excess] {
+				delete(rs.Notified, k)
+			}
+		}
+		st.Repos[l.key] = rs
+	})
+}
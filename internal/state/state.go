@@ -0,0 +1,181 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// State is the data persisted between runs
+type State struct {
+	LastUpdateCheck time.Time            `json:"last_update_check,omitempty"`
+	Repos           map[string]RepoState `json:"repos,omitempty"`
+}
+
+// RepoState is the data persisted for a single repository, keyed by "owner/repo",
+// or for an org or search view, keyed by "owner:name" or "query:name"
+type RepoState struct {
+	StarMilestone      int               `json:"star_milestone,omitempty"`
+	LastCheckID        int               `json:"last_check_id,omitempty"` // ID of the newest issue already handled
+	Notified           map[int]time.Time `json:"notified,omitempty"`      // Issue ID to when it was notified
+	MilestoneScan      time.Time         `json:"milestone_scan,omitempty"`
+	MilestoneReminders map[string]bool   `json:"milestone_reminders,omitempty"` // "milestone:issue" pairs already reminded about
+}
+
+// Store persists State as a JSON file, optionally encrypted at rest
+type Store struct {
+	path       string
+	passphrase string
+	key        *fileKey // Derived from passphrase on first use, see fileKey
+	mu         sync.Mutex
+	state      State
+}
+
+// Option configures optional Store behavior
+type Option func(*Store)
+
+// WithPassphrase encrypts the state file with a key derived from passphrase
+func WithPassphrase(passphrase string) Option {
+	return func(s *Store) {
+		s.passphrase = passphrase
+	}
+}
+
+// DefaultPath returns the default state file location under the user's config directory
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("error locating config directory: %v", err)
+	}
+	return filepath.Join(dir, "gitnotifier", "state.json"), nil
+}
+
+// NewStore creates a store backed by the given file without reading it
+func NewStore(path string, opts ...Option) *Store {
+	s := &Store{path: path}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// IsKeyError reports whether err means the state file can't be read with the configured key.
+// Unlike corruption, this should not be recovered from by discarding the file.
+func IsKeyError(err error) bool {
+	return errors.Is(err, ErrWrongKey) || errors.Is(err, ErrKeyRequired)
+}
+
+// Load reads the state file. A missing file is not an error and leaves the
+// state empty; a corrupt file returns an error with the state left empty.
+func (s *Store) Load() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error reading state file: %v", err)
+	}
+
+	// Plaintext files are accepted when a key is set and get encrypted on the next save
+	if IsEncrypted(data) {
+		if s.passphrase == "" {
+			return ErrKeyRequired
+		}
+		if data, s.key, err = decrypt(s.passphrase, s.key, data); err != nil {
+			return err
+		}
+	}
+
+	var st State
+	if err := json.Unmarshal(data, &st); err != nil {
+		return fmt.Errorf("error parsing state file %s: %v", s.path, err)
+	}
+	s.state = st
+	return nil
+}
+
+// Get returns a copy of the current state
+func (s *Store) Get() State {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st := s.state
+	st.Repos = make(map[string]RepoState, len(s.state.Repos))
+	for k, v := range s.state.Repos {
+		v.Notified = maps.Clone(v.Notified)
+		v.MilestoneReminders = maps.Clone(v.MilestoneReminders)
+		st.Repos[k] = v
+	}
+	return st
+}
+
+// Update applies fn to the state and writes the result to disk
+func (s *Store) Update(fn func(*State)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fn(&s.state)
+	return s.save()
+}
+
+// ResetRepo removes the persisted state stored under key, including the issues
+// it notified, and reports whether there was any
+func (s *Store) ResetRepo(key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.state.Repos[key]; !ok {
+		return false, nil
+	}
+	delete(s.state.Repos, key)
+	return true, s.save()
+}
+
+// Reset wipes all persisted state
+func (s *Store) Reset() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.state = State{}
+	return s.save()
+}
+
+// save writes the state atomically via a temporary file and rename
+func (s *Store) save() error {
+	data, err := json.MarshalIndent(s.state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding state: %v", err)
+	}
+
+	if s.passphrase != "" {
+		if s.key == nil {
+			if s.key, err = newFileKey(s.passphrase, nil); err != nil {
+				return err
+			}
+		}
+		if data, err = s.key.encrypt(data); err != nil {
+			return err
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("error creating state directory: %v", err)
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("error writing state file: %v", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("error writing state file: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package state
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestResetRepoClearsNotifiedIssues(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	store := NewStore(path)
+	for _, key := range []string{"owner/repo", "owner:org", "query:triage"} {
+		if err := NewNotifiedLog(store, key, time.Hour, 10).Record(1); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	found, err := store.ResetRepo("owner:org")
+	if err != nil || !found {
+		t.Fatalf("ResetRepo = %v, %v, want true, nil", found, err)
+	}
+
+	// Reload to check what was written
+	reloaded := NewStore(path)
+	if err := reloaded.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if NewNotifiedLog(reloaded, "owner:org", time.Hour, 10).Seen(1) {
+		t.Error("issue still notified for the reset key")
+	}
+	for _, key := range []string{"owner/repo", "query:triage"} {
+		if !NewNotifiedLog(reloaded, key, time.Hour, 10).Seen(1) {
+			t.Errorf("issue no longer notified for %s, which wasn't reset", key)
+		}
+	}
+
+	if found, err := store.ResetRepo("owner:org"); err != nil || found {
+		t.Errorf("second ResetRepo = %v, %v, want false, nil", found, err)
+	}
+}
+
+func TestNotifiedLogExpiresAndTrims(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "state.json"))
+	log := NewNotifiedLog(store, "owner/repo", time.Hour, 2)
+	for _, id := range []int{1, 2, 3} {
+		if err := log.Record(id); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if log.Seen(1) || !log.Seen(2) || !log.Seen(3) {
+		t.Errorf("got %v, want only the two newest issues kept", store.Get().Repos["owner/repo"].Notified)
+	}
+
+	expired := NewNotifiedLog(store, "owner/repo", time.Nanosecond, 2)
+	if expired.Seen(3) {
+		t.Error("issue seen after its ttl passed")
+	}
+}
+
+func TestEncryptedStoreDerivesKeyOnce(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	store := NewStore(path, WithPassphrase("secret"))
+	log := NewNotifiedLog(store, "owner/repo", time.Hour, 10)
+	if err := log.Record(1); err != nil {
+		t.Fatal(err)
+	}
+	key := store.key
+	if err := log.Record(2); err != nil {
+		t.Fatal(err)
+	}
+	if store.key != key {
+		t.Error("key derived again on the second save")
+	}
+
+	reloaded := NewStore(path, WithPassphrase("secret"))
+	if err := reloaded.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if !NewNotifiedLog(reloaded, "owner/repo", time.Hour, 10).Seen(2) {
+		t.Error("issue not notified after reloading the encrypted file")
+	}
+	if !bytes.Equal(reloaded.key.salt, key.salt) {
+		t.Error("reloaded store doesn't reuse the salt of the file")
+	}
+
+	if err := NewStore(path, WithPassphrase("wrong")).Load(); !IsKeyError(err) {
+		t.Errorf("Load with the wrong passphrase = %v, want a key error", err)
+	}
+	if err := NewStore(path).Load(); !IsKeyError(err) {
+		t.Errorf("Load without a passphrase = %v, want a key error", err)
+	}
+}
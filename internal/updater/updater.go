@@ -0,0 +1,58 @@
+package updater
+
+import (
+	"context"
+	"fmt"
+	"gitnotifier/config"
+	"gitnotifier/internal/notifier"
+	"gitnotifier/internal/repository"
+	"gitnotifier/internal/state"
+	"gitnotifier/internal/version"
+	"log/slog"
+	"time"
+)
+
+// Owner and Repo identify the repository gitnotifier releases are published to
+const (
+	Owner = "Vedant-Gandhi"
+	Repo  = "Github-Notifier"
+)
+
+// ReleaseFetcher defines the interface for looking up the latest release
+type ReleaseFetcher interface {
+	FetchLatestRelease(ctx context.Context) (*repository.Release, error)
+}
+
+// Check looks for a newer gitnotifier release and sends a single notification
+// if one exists. Checks are limited to once per config.UpdateCheckInterval
+// using the persisted state.
+func Check(ctx context.Context, releases ReleaseFetcher, n notifier.Notifier, store *state.Store) error {
+	if !version.IsRelease() {
+		slog.Info("Skipping update check for development build", "event", "update_check", "version", version.Version)
+		return nil
+	}
+
+	if last := store.Get().LastUpdateCheck; time.Since(last) < config.UpdateCheckInterval {
+		return nil
+	}
+
+	release, err := releases.FetchLatestRelease(ctx)
+	if err != nil {
+		return fmt.Errorf("error checking for updates: %v", err)
+	}
+
+	if err := store.Update(func(s *state.State) { s.LastUpdateCheck = time.Now() }); err != nil {
+		slog.Error("Error saving update check time", "event", "update_check", "error", err)
+	}
+
+	if !version.IsNewer(release.TagName, version.Version) {
+		return nil
+	}
+
+	slog.Warn("A newer version of gitnotifier is available", "event", "update_check", "latest", release.TagName, "version", version.Version)
+	return n.Notify(
+		"gitnotifier update available",
+		fmt.Sprintf("Version %s is available (running %s)", release.TagName, version.Version),
+		release.HTMLURL,
+	)
+}
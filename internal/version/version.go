@@ -0,0 +1,59 @@
+package version
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Version is the build version, set at build time with
+// -ldflags "-X gitnotifier/internal/version.Version=v1.2.3"
+var Version = "dev"
+
+// IsRelease reports whether the build carries a comparable version
+func IsRelease() bool {
+	_, ok := parse(Version)
+	return ok
+}
+
+// IsNewer reports whether latest is a higher semantic version than current.
+// Versions that cannot be parsed are never considered newer.
+func IsNewer(latest, current string) bool {
+	l, ok := parse(latest)
+	if !ok {
+		return false
+	}
+	c, ok := parse(current)
+	if !ok {
+		return false
+	}
+
+	for i := range l {
+		if l[i] != c[i] {
+			return l[i] > c[i]
+		}
+	}
+	return false
+}
+
+// parse converts "v1.2.3" (pre-release and build suffixes ignored) into its numeric parts
+func parse(v string) ([3]int, bool) {
+	var parts [3]int
+
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+
+	fields := strings.Split(v, ".")
+	if len(fields) == 0 || len(fields) > 3 {
+		return parts, false
+	}
+	for i, f := range fields {
+		n, err := strconv.Atoi(f)
+		if err != nil || n < 0 {
+			return parts, false
+		}
+		parts[i] = n
+	}
+	return parts, true
+}
@@ -0,0 +1,181 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gitnotifier/internal/httpserver"
+	"gitnotifier/internal/issue"
+	"gitnotifier/internal/notifier"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxPayloadSize is the largest payload GitHub sends, see its webhook docs
+const maxPayloadSize = 25 << 20
+
+// issuesEvent is the part of an "issues" webhook payload the receiver uses
+type issuesEvent struct {
+	Action string      `json:"action"`
+	Issue  issue.Issue `json:"issue"`
+}
+
+// Receiver accepts GitHub webhook deliveries and notifies about issues being
+// opened, reopened and closed. Every delivery must be signed with the shared
+// secret in X-Hub-Signature-256.
+type Receiver struct {
+	secret     []byte
+	notifier   *notifier.IssueNotifier
+	deliveries deliveries
+}
+
+// NewReceiver creates a Receiver that notifies through n. secret must not be
+// empty. A delivery ID seen again within deliveryTTL is ignored, and 0 turns
+// that off.
+func NewReceiver(secret string, n *notifier.IssueNotifier, deliveryTTL time.Duration) (*Receiver, error) {
+	if secret == "" {
+		return nil, fmt.Errorf("a webhook secret is required")
+	}
+	return &Receiver{
+		secret:     []byte(secret),
+		notifier:   n,
+		deliveries: deliveries{ttl: deliveryTTL, seen: make(map[string]time.Time)},
+	}, nil
+}
+
+// Handler returns the webhook route
+func (r *Receiver) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /webhook", r.handleDelivery)
+	return mux
+}
+
+// Run serves the webhook endpoint on addr until ctx is cancelled
+func (r *Receiver) Run(ctx context.Context, addr string) error {
+	return httpserver.Run(ctx, addr, r.Handler(), "webhooks", "webhook")
+}
+
+func (r *Receiver) handleDelivery(w http.ResponseWriter, req *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(req.Body, maxPayloadSize))
+	if err != nil {
+		http.Error(w, "error reading payload", http.StatusBadRequest)
+		return
+	}
+	if !r.validSignature(req.Header.Get("X-Hub-Signature-256"), body) {
+		slog.Warn("Rejected webhook delivery with a missing or invalid signature", "event", "webhook", "remote_addr", req.RemoteAddr)
+		http.Error(w, "missing or invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	// GitHub redelivers on timeouts, so the same delivery may arrive twice
+	delivery := req.Header.Get("X-GitHub-Delivery")
+	if delivery != "" && !r.deliveries.claim(delivery) {
+		slog.Info("Ignoring repeated webhook delivery", "event", "webhook", "delivery", delivery)
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if req.Header.Get("X-GitHub-Event") != "issues" {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	var event issuesEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	if err := r.notify(event); err != nil {
+		slog.Error("Error sending notification", "event", "webhook", "action", event.Action, "issue_number", event.Issue.Number, "error", err)
+		if delivery != "" {
+			r.deliveries.release(delivery)
+		}
+		http.Error(w, "error sending notification", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// notify sends the notification for an issues event, ignoring actions other than opened, reopened and closed
+func (r *Receiver) notify(event issuesEvent) error {
+	var err error
+	switch event.Action {
+	case "opened":
+		err = r.notifier.NotifyNewIssue(event.Issue)
+	case "reopened":
+		err = r.notifier.NotifyStateChange(event.Issue, "closed", "open")
+	case "closed":
+		err = r.notifier.NotifyStateChange(event.Issue, "open", "closed")
+	default:
+		return nil
+	}
+	if errors.Is(err, notifier.ErrAlreadySent) {
+		slog.Info("Skipping issue already notified", "event", "webhook", "action", event.Action, "issue_number", event.Issue.Number)
+		return nil
+	}
+	if err == nil {
+		slog.Info("Sent notification for webhook event", "event", "webhook", "action", event.Action, "issue_number", event.Issue.Number, "repo", event.Issue.RepoFullName())
+	}
+	return err
+}
+
+// validSignature checks the "sha256=<hex>" HMAC of body against the shared secret
+func (r *Receiver) validSignature(header string, body []byte) bool {
+	sig, ok := strings.CutPrefix(header, "sha256=")
+	if !ok {
+		return false
+	}
+	got, err := hex.DecodeString(sig)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, r.secret)
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
+
+// deliveries remembers recent X-GitHub-Delivery IDs for ttl
+type deliveries struct {
+	ttl  time.Duration
+	mu   sync.Mutex
+	seen map[string]time.Time
+}
+
+// claim records id and reports whether it had not been seen within the window
+func (d *deliveries) claim(id string) bool {
+	if d.ttl <= 0 {
+		return true
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := time.Now()
+	for k, t := range d.seen {
+		if now.Sub(t) >= d.ttl {
+			delete(d.seen, k)
+		}
+	}
+
+	if _, ok := d.seen[id]; ok {
+		return false
+	}
+	d.seen[id] = now
+	return true
+}
+
+// release forgets id so GitHub's redelivery of a failed notification is handled
+func (d *deliveries) release(id string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.seen, id)
+}
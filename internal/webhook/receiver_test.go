@@ -0,0 +1,124 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"gitnotifier/config"
+	"gitnotifier/internal/notifier"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const testSecret = "s3cret"
+
+const openedPayload = `{"action":"opened","issue":{"id":142,"number":42,"title":"Crash on start",` +
+	`"html_url":"https://github.com/owner/repo/issues/42","user":{"login":"alice"}}}`
+
+// countingNotifier is a fake Notifier that counts notifications
+type countingNotifier struct {
+	mu   sync.Mutex
+	sent int
+}
+
+func (c *countingNotifier) Notify(title, message, url string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sent++
+	return nil
+}
+
+func (c *countingNotifier) count() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.sent
+}
+
+func newTestReceiver(t *testing.T) (*Receiver, *countingNotifier) {
+	t.Helper()
+	n := &countingNotifier{}
+	r, err := NewReceiver(testSecret, notifier.NewIssueNotifier(n), config.WebhookDeliveryTTL)
+	if err != nil {
+		t.Fatalf("NewReceiver() error = %v", err)
+	}
+	return r, n
+}
+
+// deliver posts a signed issues event with the given delivery ID
+func deliver(t *testing.T, srv *httptest.Server, delivery string) {
+	t.Helper()
+	mac := hmac.New(sha256.New, []byte(testSecret))
+	mac.Write([]byte(openedPayload))
+
+	req, err := http.NewRequest(http.MethodPost, srv.URL+"/webhook", strings.NewReader(openedPayload))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("X-GitHub-Event", "issues")
+	req.Header.Set("X-GitHub-Delivery", delivery)
+	req.Header.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatalf("delivery failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		t.Fatalf("delivery %s got status %d", delivery, resp.StatusCode)
+	}
+}
+
+func TestReceiverIgnoresRepeatedDelivery(t *testing.T) {
+	r, n := newTestReceiver(t)
+	srv := httptest.NewServer(r.Handler())
+	defer srv.Close()
+
+	deliver(t, srv, "72d3162e-cc78-11e3-81ab-4c9367dc0958")
+	deliver(t, srv, "72d3162e-cc78-11e3-81ab-4c9367dc0958")
+	if got := n.count(); got != 1 {
+		t.Errorf("sent %d notifications for a repeated delivery, want 1", got)
+	}
+
+	deliver(t, srv, "9a4f0c1e-cc78-11e3-81ab-4c9367dc0958")
+	if got := n.count(); got != 2 {
+		t.Errorf("sent %d notifications after a new delivery, want 2", got)
+	}
+}
+
+func TestReceiverForgetsDeliveryAfterTTL(t *testing.T) {
+	r, n := newTestReceiver(t)
+	srv := httptest.NewServer(r.Handler())
+	defer srv.Close()
+
+	const id = "72d3162e-cc78-11e3-81ab-4c9367dc0958"
+	deliver(t, srv, id)
+	r.deliveries.seen[id] = time.Now().Add(-config.WebhookDeliveryTTL)
+	deliver(t, srv, id)
+	if got := n.count(); got != 2 {
+		t.Errorf("sent %d notifications for a delivery repeated after the TTL, want 2", got)
+	}
+}
+
+func TestReceiverRejectsInvalidSignature(t *testing.T) {
+	r, n := newTestReceiver(t)
+	srv := httptest.NewServer(r.Handler())
+	defer srv.Close()
+
+	req, _ := http.NewRequest(http.MethodPost, srv.URL+"/webhook", strings.NewReader(openedPayload))
+	req.Header.Set("X-GitHub-Event", "issues")
+	req.Header.Set("X-Hub-Signature-256", "sha256=00")
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusUnauthorized)
+	}
+	if got := n.count(); got != 0 {
+		t.Errorf("sent %d notifications for an unsigned delivery", got)
+	}
+}
@@ -2,26 +2,53 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
+	"fmt"
 	"gitnotifier/config"
+	"gitnotifier/internal/api"
 	"gitnotifier/internal/github"
+	"gitnotifier/internal/metrics"
 	"gitnotifier/internal/notifier"
+	"gitnotifier/internal/notifier/platform"
 	"gitnotifier/internal/repository"
 	"gitnotifier/internal/service"
+	"gitnotifier/internal/updater"
+	"gitnotifier/internal/version"
 	"log"
-	"net/http"
+	"log/slog"
 	"os"
 	"os/signal"
+	"strconv"
+	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
 	"github.com/joho/godotenv"
+	"golang.org/x/time/rate"
 )
 
 func main() {
 	// Add command line flag for env file path
 	envFile := flag.String("env", "", "Path to environment file")
-	flag.Parse()
+	configFile := flag.String("config", "", "Path or http(s) URL of a YAML or JSON config file; environment variables take precedence")
+	traceIssue := flag.Int("trace-issue", 0, "Log every filtering decision made about this issue number")
+	resetState := flag.String("reset-state", "", "Clear persisted state for owner/repo, owner:name, query:name (or 'all') and exit")
+	tail := flag.Bool("tail", false, "Print new issues to the terminal instead of showing desktop notifications")
+	once := flag.Bool("once", false, "Check every watched repo and query once, then exit non-zero if any check failed")
+	list := flag.Bool("list", false, "Print the open issues of every watched repo and exit; also available as the 'list' subcommand")
+	jsonOutput := flag.Bool("json", false, "With --list, print the issues as JSON")
+	serve := flag.Bool("serve", false, "Notify from GitHub webhooks received on WEBHOOK_ADDR instead of polling; also available as the 'serve' subcommand")
+
+	// "gitnotifier list [flags]" is the same as "gitnotifier --list [flags]", likewise for serve
+	subcommands := map[string]*bool{"list": list, "serve": serve}
+	if len(os.Args) > 1 && subcommands[os.Args[1]] != nil {
+		*subcommands[os.Args[1]] = true
+		flag.CommandLine.Parse(os.Args[2:])
+	} else {
+		flag.Parse()
+	}
 
 	// Load environment file if specified, otherwise try default .env
 	if *envFile != "" {
@@ -29,53 +56,116 @@ func main() {
 			log.Fatalf("Error loading environment file %s: %v", *envFile, err)
 		}
 	} else if err := godotenv.Load(); err != nil {
-		log.Printf("Error loading .env file: %v", err)
+		slog.Warn("Error loading .env file", "error", err)
+	}
+
+	// Optional config file, filling in whatever the environment doesn't set
+	if *configFile != "" {
+		cfg, err := loadConfig(*configFile)
+		if err != nil {
+			log.Fatalf("Failed to load config file: %v", err)
+		}
+		if err := cfg.Apply(); err != nil {
+			log.Fatalf("Failed to load config file: %v", err)
+		}
+	}
+
+	// Optional structured logs and verbosity, e.g. LOG_FORMAT=json LOG_LEVEL=warn
+	if err := configureLogging(os.Getenv("LOG_FORMAT"), os.Getenv("LOG_LEVEL")); err != nil {
+		log.Fatalf("Invalid logging configuration: %v", err)
 	}
 
-	// Rest of the code remains the same
-	repoURL := os.Getenv("GITHUB_REPO_URL")
-	if repoURL == "" {
+	if *resetState != "" {
+		if err := runResetState(*resetState); err != nil {
+			log.Fatalf("Failed to reset state: %v", err)
+		}
+		return
+	}
+
+	if *serve {
+		if err := runWebhookReceiver(*tail); err != nil {
+			log.Fatalf("Webhook receiver failed: %v", err)
+		}
+		return
+	}
+
+	repoURLs := config.SplitList(os.Getenv("GITHUB_REPO_URL"))
+	if len(repoURLs) == 0 {
 		log.Fatal("GITHUB_REPO_URL environment variable is not set")
 	}
 
-	// Parse GitHub repository URL
-	owner, repo, err := github.ParseGitHubURL(repoURL)
+	// Optional GitHub Enterprise Server, e.g. GITHUB_API_BASE=https://github.example.com
+	apiBase, webBase, err := github.ResolveAPIBase(os.Getenv("GITHUB_API_BASE"))
 	if err != nil {
-		log.Fatalf("Invalid repository URL: %v", err)
+		log.Fatalf("Invalid GITHUB_API_BASE: %v", err)
 	}
 
-	// Get poll interval from environment
-	pollInterval := config.DefaultPollInterval
-	if envInterval := os.Getenv("POLL_INTERVAL"); envInterval != "" {
-		if d, err := time.ParseDuration(envInterval); err == nil {
-			if d < config.MinPollInterval {
-				d = config.MinPollInterval
-			}
-			pollInterval = d
-		}
+	// Parse the comma-separated GitHub repository URLs. The first repository
+	// scopes QUERIES and hosts the features that aren't tied to a repository.
+	// User or org URLs such as https://github.com/someorg watch all their repos
+	watchedRepos, watchedOwners, err := parseWatched(repoURLs, webBase)
+	if err != nil {
+		log.Fatalf("Invalid GITHUB_REPO_URL: %v", err)
 	}
 
-	// Create HTTP client
-	client := &http.Client{
-		Timeout: config.HTTPTimeout,
+	// Without a repository the first owner scopes QUERIES instead
+	var owner, repo string
+	if len(watchedRepos) > 0 {
+		owner, repo = watchedRepos[0].owner, watchedRepos[0].repo
+	} else {
+		owner = watchedOwners[0].owner
 	}
 
-	// Initialize repository
-	githubRepo := repository.NewRepository(
-		client,
-		owner,
-		repo,
-		os.Getenv("GITHUB_TOKEN"),
-	)
+	// Get poll interval from environment
+	pollInterval := service.PollIntervalFromEnv()
 
-	// Initialize platform-specific notifier
-	notifier, err := notifier.NewPlatformNotifier()
+	// Create the GitHub HTTP client, optionally through GITHUB_PROXY_URL
+	githubProxy, err := parseProxyURL(os.Getenv("GITHUB_PROXY_URL"))
 	if err != nil {
-		log.Fatalf("Failed to initialize notifier: %v", err)
+		log.Fatalf("Invalid GITHUB_PROXY_URL: %v", err)
+	}
+	client := newHTTPClient(githubProxy)
+
+	// Pagination limits, pull requests and authentication mode
+	repoOpts, err := repository.OptionsFromEnv(client, apiBase)
+	if err != nil {
+		log.Fatalf("Invalid repository configuration: %v", err)
+	}
+
+	// Optional per-owner or per-repository tokens, falling back to GITHUB_TOKEN
+	defaultToken := os.Getenv("GITHUB_TOKEN")
+	tokens, err := github.ParseTokens(os.Getenv("GITHUB_TOKENS"))
+	if err != nil {
+		log.Fatalf("Invalid GITHUB_TOKENS: %v", err)
+	}
+	var accessChecks []tokenAccess
+
+	// Initialize repositories
+	for i := range watchedRepos {
+		w := &watchedRepos[i]
+		token := tokens.For(w.owner, w.repo, defaultToken)
+		accessChecks = append(accessChecks, tokenAccess{owner: w.owner, repo: w.repo, token: token})
+		w.client = repository.NewRepository(client, w.owner, w.repo, token, repoOpts...)
+	}
+	for i := range watchedOwners {
+		w := &watchedOwners[i]
+		token := tokens.For(w.owner, "", defaultToken)
+		accessChecks = append(accessChecks, tokenAccess{owner: w.owner, token: token})
+		w.client = repository.NewOwnerRepository(client, w.owner, token, repoOpts...)
+	}
+	var githubRepo *repository.Repository
+	if len(watchedRepos) > 0 {
+		githubRepo = watchedRepos[0].client
+	} else {
+		githubRepo = watchedOwners[0].client.Repository
 	}
 
-	// Create notification service
-	service := service.NewService(githubRepo, notifier, pollInterval)
+	if *list {
+		if err := runList(context.Background(), watchedRepos, watchedOwners, *jsonOutput); err != nil {
+			log.Fatalf("Failed to list issues: %v", err)
+		}
+		return
+	}
 
 	// Setup context with cancellation
 	ctx, cancel := context.WithCancel(context.Background())
@@ -87,12 +177,332 @@ func main() {
 
 	go func() {
 		sig := <-sigChan
-		log.Printf("Received signal %v, initiating shutdown...", sig)
+		slog.Info("Received signal, initiating shutdown", "event", "stop", "signal", sig.String())
 		cancel()
 	}()
 
-	// Start the service
-	if err := service.Start(ctx); err != nil {
-		log.Fatalf("Service error: %v", err)
+	// With NOTIFY_REPLACE_IN_PLACE the desktop notification counts the new
+	// issues that are still open, tracked across all services
+	var openIssues *notifier.OpenIssues
+	if replace, _ := strconv.ParseBool(os.Getenv("NOTIFY_REPLACE_IN_PLACE")); replace {
+		openIssues = notifier.NewOpenIssues()
+	}
+
+	// Initialize notifier
+	issueNotifier, err := notifier.FromEnv(ctx, newHTTPClient(nil), *tail, openIssues, func() platform.Icons {
+		return ownerAvatars(client, watchedRepos)
+	})
+	if err != nil {
+		log.Fatalf("Failed to initialize notifier: %v", err)
+	}
+
+	// Counters shared by the metrics exporters
+	serviceMetrics := metrics.New()
+	issueNotifier = metrics.CountNotifications(issueNotifier, serviceMetrics)
+
+	// Delivery can be paused with SIGUSR1/SIGUSR2 or while PAUSE_FILE exists
+	pausable := notifier.NewPausableNotifier(issueNotifier, os.Getenv("PAUSE_FILE"))
+	handlePauseSignals(pausable)
+	issueNotifier = pausable
+
+	opts := []service.Option{service.WithMetrics(serviceMetrics)}
+	if openIssues != nil {
+		opts = append(opts, service.WithOpenIssues(openIssues))
+	}
+	if *traceIssue > 0 {
+		opts = append(opts, service.WithTraceIssue(*traceIssue))
+	}
+
+	// Formatting, filters and deduplication shared by every service
+	sharedOpts, err := service.OptionsFromEnv()
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	opts = append(opts, sharedOpts...)
+	features, err := service.FeaturesFromEnv()
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	if features.FiltersNumbers() && (len(watchedRepos) != 1 || len(watchedOwners) > 0) {
+		log.Fatal("MUTE_ISSUES and WATCH_ISSUES require GITHUB_REPO_URL to list exactly one repository")
+	}
+
+	// Persisted state: the last seen issue of each service, star milestones and update checks
+	store, err := loadState()
+	if err != nil {
+		log.Fatalf("Failed to load state: %v", err)
+	}
+
+	// Optional once-a-day check for a newer gitnotifier release
+	if checkUpdates, _ := strconv.ParseBool(os.Getenv("CHECK_FOR_UPDATES")); checkUpdates {
+		// Releases are published on github.com, where Enterprise credentials don't apply
+		releases := repository.NewRepository(client, updater.Owner, updater.Repo, os.Getenv("GITHUB_TOKEN"), repoOpts...)
+		if apiBase != config.DefaultAPIBase {
+			releases = repository.NewRepository(client, updater.Owner, updater.Repo, "")
+		}
+		go func() {
+			if err := updater.Check(context.Background(), releases, issueNotifier, store); err != nil {
+				slog.Error("Update check failed", "event", "update_check", "error", err)
+			}
+		}()
+	}
+
+	// Create notification service. All services share one rate limiter
+	opts = append(opts, service.WithLimiter(rate.NewLimiter(rate.Every(time.Minute), 30)))
+
+	// Optional summary notification once every service has completed its first poll
+	notifyOnStart, _ := strconv.ParseBool(os.Getenv("NOTIFY_ON_START"))
+	var startup startupSummary
+	if notifyOnStart {
+		opts = append(opts, service.WithInitialCheckHook(startup.record))
+	}
+
+	// Optional Projects v2 watch, e.g. PROJECT_FIELD=Sprint PROJECT_FIELD_VALUE=@current
+	var projectOpt service.Option
+	if projectID := os.Getenv("PROJECT_ID"); projectID != "" {
+		field, value := os.Getenv("PROJECT_FIELD"), os.Getenv("PROJECT_FIELD_VALUE")
+		if field == "" || value == "" {
+			log.Fatal("PROJECT_FIELD and PROJECT_FIELD_VALUE must be set when PROJECT_ID is set")
+		}
+		// The project may belong to another account than the watched repositories
+		projectToken := os.Getenv("PROJECT_TOKEN")
+		if projectToken == "" {
+			projectToken = defaultToken
+		}
+		project := repository.NewProjectRepository(client, projectToken, projectID, field, repoOpts...)
+		projectOpt = service.WithProjectWatch(project, field, value)
+	}
+
+	// Optional REST API on API_ADDR, authenticated with API_AUTH_TOKEN
+	apiAddr := os.Getenv("API_ADDR")
+	var recent *service.RecentIssues
+	if apiAddr != "" {
+		recent = service.NewRecentIssues(config.RecentIssuesSize)
+		opts = append(opts, service.WithRecentIssues(recent))
+	}
+	var apiWatched []api.Watched
+
+	// One service per watched repository, each resuming from its own last seen
+	// issue. With several repositories notifications are tagged with owner/repo
+	var services []*service.Service
+	for i, w := range watchedRepos {
+		featureOpts, err := features.RepoOptions(context.Background(), w.client, store, w.key())
+		if err != nil {
+			log.Fatalf("Failed to set up %s: %v", w.key(), err)
+		}
+		svcOpts := append(opts[:len(opts):len(opts)], service.WithLogger(slog.Default().With("repo", w.key())))
+		svcOpts = append(svcOpts, featureOpts...)
+		if len(watchedRepos)+len(watchedOwners) > 1 {
+			svcOpts = append(svcOpts, service.WithName(w.key()))
+		}
+		if i == 0 && projectOpt != nil {
+			svcOpts = append(svcOpts, projectOpt)
+		}
+		services = append(services, service.NewService(w.client, issueNotifier, pollInterval, svcOpts...))
+		apiWatched = append(apiWatched, api.Watched{Label: w.key(), Service: services[len(services)-1]})
+	}
+
+	// One search-based service per watched user or org, tagging notifications
+	// with the repository of each issue
+	for _, w := range watchedOwners {
+		ownerOpts := append(opts[:len(opts):len(opts)],
+			service.WithName(w.owner),
+			service.WithRepoTags(),
+			service.WithLogger(slog.Default().With("owner", w.owner)))
+		ownerOpts = append(ownerOpts, features.SearchOptions(w.client, w.client, store, "owner:"+w.owner)...)
+		services = append(services, service.NewService(w.client, issueNotifier, pollInterval, ownerOpts...))
+		apiWatched = append(apiWatched, api.Watched{Label: "owner:" + w.owner, Service: services[len(services)-1]})
+	}
+
+	// Optional named search queries, each monitored as its own view
+	if spec := os.Getenv("QUERIES"); spec != "" {
+		queries, err := github.ParseQueries(spec)
+		if err != nil {
+			log.Fatalf("Invalid QUERIES: %v", err)
+		}
+		for _, q := range queries {
+			query := github.ScopeQuery(q.Query, owner, repo)
+			queryOwner, queryRepo, _ := github.QueryTarget(query)
+			token := tokens.For(queryOwner, queryRepo, defaultToken)
+			accessChecks = append(accessChecks, tokenAccess{owner: queryOwner, repo: queryRepo, token: token})
+
+			searchRepo := repository.NewSearchRepository(client, query, token, repoOpts...)
+			queryOpts := append(opts[:len(opts):len(opts)],
+				service.WithName(q.Name),
+				service.WithLogger(slog.Default().With("query", q.Name)))
+			queryOpts = append(queryOpts, features.SearchOptions(searchRepo, githubRepo, store, "query:"+q.Name)...)
+			services = append(services, service.NewService(searchRepo, issueNotifier, pollInterval, queryOpts...))
+			apiWatched = append(apiWatched, api.Watched{Label: "query:" + q.Name, Service: services[len(services)-1]})
+		}
+	}
+
+	if len(tokens) > 0 {
+		checkTokenAccess(ctx, client, accessChecks, repoOpts)
+	}
+
+	// The startup summary is sent in the background once every service has
+	// polled, or after the checks in once mode
+	notifyStartup := func() {
+		baselined, failed := startup.result()
+		url := fmt.Sprintf("%s/%s", webBase, owner)
+		if repo != "" {
+			url += "/" + repo
+		}
+		if err := notifier.NewIssueNotifier(issueNotifier).NotifyStartup(version.Version, len(services), baselined, failed, url); err != nil {
+			slog.Error("Error sending startup notification", "event", "start", "error", err)
+		}
+	}
+	if notifyOnStart {
+		startup.wait(len(services))
+	}
+
+	if os.Getenv("PAUSE_FILE") != "" {
+		go pausable.Run(ctx, config.PauseFileCheckInterval)
+	}
+
+	if apiAddr != "" {
+		server, err := api.NewServer(os.Getenv("API_AUTH_TOKEN"), apiWatched, recent, pausable)
+		if err != nil {
+			log.Fatalf("Invalid REST API configuration: %v", err)
+		}
+		go func() {
+			if err := server.Run(ctx, apiAddr); err != nil {
+				slog.Error("REST API stopped", "event", "api", "error", err)
+			}
+		}()
+	}
+
+	// Optional /healthz and /metrics endpoints, e.g. HEALTH_ADDR=:8080
+	if addr := os.Getenv("HEALTH_ADDR"); addr != "" {
+		health := metrics.NewHealthServer(serviceMetrics, func() error {
+			return servicesHealth(apiWatched)
+		})
+		go func() {
+			if err := health.Run(ctx, addr); err != nil {
+				slog.Error("Health endpoints stopped", "event", "health", "error", err)
+			}
+		}()
+	}
+
+	// Optional StatsD exporter
+	if addr := os.Getenv("STATSD_ADDR"); addr != "" {
+		prefix := os.Getenv("STATSD_PREFIX")
+		if prefix == "" {
+			prefix = "gitnotifier"
+		}
+		statsd := metrics.NewStatsD(serviceMetrics, addr, prefix, config.MetricsFlushInterval)
+		go func() {
+			if err := statsd.Run(ctx); err != nil {
+				slog.Error("StatsD exporter stopped", "event", "statsd", "error", err)
+			}
+		}()
+	}
+
+	// Single check for cron jobs and CI, also enabled with RUN_ONCE=true
+	if runOnce, _ := strconv.ParseBool(os.Getenv("RUN_ONCE")); *once || runOnce {
+		failed := 0
+		for _, svc := range services {
+			if err := svc.RunOnce(ctx); err != nil {
+				slog.Error("Check failed", "event", "poll", "error", err)
+				failed++
+			}
+		}
+		if notifyOnStart {
+			notifyStartup()
+		}
+		if failed > 0 {
+			log.Fatalf("%d of %d checks failed", failed, len(services))
+		}
+		return
+	}
+
+	if notifyOnStart {
+		go notifyStartup()
+	}
+
+	// Start the services. One that gives up, e.g. after repeated panics, stops
+	// on its own while the others keep running, and the exit status reports it.
+	var wg sync.WaitGroup
+	var failed atomic.Int32
+	for _, svc := range services {
+		wg.Add(1)
+		go func(svc *service.Service) {
+			defer wg.Done()
+			if err := svc.Start(ctx); err != nil {
+				slog.Error("Service stopped", "event", "stop", "error", err)
+				failed.Add(1)
+			}
+		}(svc)
+	}
+	wg.Wait()
+	if n := failed.Load(); n > 0 {
+		log.Fatalf("%d of %d services stopped with an error", n, len(services))
+	}
+}
+
+// servicesHealth reports the services whose most recent poll failed. Services
+// that have not polled yet are considered healthy.
+func servicesHealth(watched []api.Watched) error {
+	var errs []error
+	for _, w := range watched {
+		if st := w.Service.Status(); st.LastError != "" {
+			errs = append(errs, fmt.Errorf("%s: %s", w.Label, st.LastError))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// configureLogging selects the log format and the minimum level logged:
+// "text" (the default) keeps the familiar log output, "json" writes one JSON
+// object per line. Output from the standard log package goes through the same logger.
+func configureLogging(format, level string) error {
+	var minLevel slog.Level
+	if level != "" {
+		if err := minLevel.UnmarshalText([]byte(level)); err != nil {
+			return fmt.Errorf("unknown LOG_LEVEL %q. Expected debug, info, warn or error", level)
+		}
+	}
+
+	switch format {
+	case "", "text":
+		slog.SetLogLoggerLevel(minLevel)
+		return nil
+	case "json":
+		slog.SetDefault(slog.New(slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{Level: minLevel})))
+		return nil
+	default:
+		return fmt.Errorf("unknown LOG_FORMAT %q. Expected 'text' or 'json'", format)
+	}
+}
+
+// startupSummary aggregates the initial poll results of all services
+type startupSummary struct {
+	mu        sync.Mutex
+	wg        sync.WaitGroup
+	baselined int
+	failed    int
+}
+
+// wait prepares the summary to collect results from n services
+func (s *startupSummary) wait(n int) {
+	s.wg.Add(n)
+}
+
+func (s *startupSummary) record(fetched int, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	defer s.wg.Done()
+
+	s.baselined += fetched
+	if err != nil {
+		s.failed++
 	}
 }
+
+// result blocks until every service has recorded its initial poll
+func (s *startupSummary) result() (baselined, failed int) {
+	s.wg.Wait()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.baselined, s.failed
+}
@@ -0,0 +1,26 @@
+//go:build !windows
+
+package main
+
+import (
+	"gitnotifier/internal/notifier"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// handlePauseSignals pauses notifications on SIGUSR1 and resumes them on SIGUSR2
+func handlePauseSignals(p *notifier.PausableNotifier) {
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGUSR1, syscall.SIGUSR2)
+
+	go func() {
+		for sig := range sigChan {
+			if sig == syscall.SIGUSR1 {
+				p.Pause()
+			} else {
+				p.Resume()
+			}
+		}
+	}()
+}
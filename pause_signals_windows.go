@@ -0,0 +1,8 @@
+//go:build windows
+
+package main
+
+import "gitnotifier/internal/notifier"
+
+// handlePauseSignals is a no-op because Windows has no SIGUSR1/SIGUSR2; use PAUSE_FILE instead
+func handlePauseSignals(p *notifier.PausableNotifier) {}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"gitnotifier/config"
+	"gitnotifier/internal/github"
+	"gitnotifier/internal/notifier/platform"
+	"gitnotifier/internal/repository"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+// watchedRepo is one repository listed in GITHUB_REPO_URL
+type watchedRepo struct {
+	owner, repo string
+	client      *repository.Repository
+}
+
+// key identifies the repository in logs and persisted state, e.g. "owner/repo"
+func (w watchedRepo) key() string {
+	return w.owner + "/" + w.repo
+}
+
+// parseWatched splits the GITHUB_REPO_URL entries into repositories and the
+// users or orgs whose repositories are all watched, rejecting duplicates
+func parseWatched(repoURLs []string, webBase string) ([]watchedRepo, []watchedOwner, error) {
+	var repos []watchedRepo
+	var owners []watchedOwner
+	seen := make(map[string]bool)
+	for _, repoURL := range repoURLs {
+		owner, repo, err := github.ParseRepoURL(repoURL, webBase)
+		if err != nil {
+			return nil, nil, err
+		}
+		if repo == "" {
+			if seen[owner] {
+				return nil, nil, fmt.Errorf("owner %s is listed more than once", owner)
+			}
+			seen[owner] = true
+			owners = append(owners, watchedOwner{owner: owner})
+			continue
+		}
+		w := watchedRepo{owner: owner, repo: repo}
+		if seen[w.key()] {
+			return nil, nil, fmt.Errorf("repository %s is listed more than once", w.key())
+		}
+		seen[w.key()] = true
+		repos = append(repos, w)
+	}
+	return repos, owners, nil
+}
+
+// watchedOwner is a user or org whose repositories are all watched
+type watchedOwner struct {
+	owner  string
+	client *repository.SearchRepository
+}
+
+// tokenAccess is a repository, or an org or user when repo is empty, and the token used for it
+type tokenAccess struct {
+	owner, repo, token string
+}
+
+// checkTokenAccess logs, per token, which of its targets it can access. Org and
+// user wide targets have no single repository to probe and are only listed.
+func checkTokenAccess(ctx context.Context, client *http.Client, targets []tokenAccess, repoOpts []repository.Option) {
+	seen := make(map[tokenAccess]bool)
+	var order []string
+	byToken := make(map[string][]tokenAccess)
+	for _, t := range targets {
+		if seen[t] {
+			continue
+		}
+		seen[t] = true
+		if _, ok := byToken[t.token]; !ok {
+			order = append(order, t.token)
+		}
+		byToken[t.token] = append(byToken[t.token], t)
+	}
+
+	for i, token := range order {
+		for _, t := range byToken[token] {
+			if t.repo == "" {
+				slog.Info("Token is used org or user wide, not checked", "event", "token_check", "token", i+1, "owner", t.owner)
+				continue
+			}
+			info, err := repository.NewRepository(client, t.owner, t.repo, token, repoOpts...).FetchRepoInfo(ctx)
+			if err != nil {
+				slog.Error("Token cannot access repository", "event", "token_check", "token", i+1, "repo", t.owner+"/"+t.repo, "error", err)
+				continue
+			}
+			slog.Info("Token can access repository", "event", "token_check", "token", i+1, "repo", info.FullName)
+		}
+	}
+}
+
+// ownerAvatars downloads the avatar of each watched repository's owner for use
+// as a desktop notification icon. Owners whose avatar can't be fetched get none.
+func ownerAvatars(client *http.Client, watched []watchedRepo) platform.Icons {
+	ctx, cancel := context.WithTimeout(context.Background(), config.HTTPTimeout)
+	defer cancel()
+
+	icons := make(platform.Icons)
+	for _, w := range watched {
+		owner := strings.ToLower(w.owner)
+		if _, ok := icons[owner]; ok {
+			continue
+		}
+		icons[owner] = ""
+
+		info, err := w.client.FetchRepoInfo(ctx)
+		if err != nil {
+			slog.Warn("Error looking up owner avatar, notifications will have no icon", "repo", w.key(), "error", err)
+			continue
+		}
+		path, err := platform.DownloadIcon(ctx, client, owner, info.Owner.AvatarURL)
+		if err != nil {
+			slog.Warn("Error downloading owner avatar, notifications will have no icon", "repo", w.key(), "error", err)
+			continue
+		}
+		icons[owner] = path
+	}
+	return icons
+}